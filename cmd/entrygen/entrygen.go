@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/tlarsendataguy/goalteryx/sdk/tool_def"
+	"go/format"
+	"go/token"
+	"path/filepath"
+	"sort"
+)
+
+type entryPoint struct {
+	tool     string
+	function string
+}
+
+func generateEntryPoints(pkg string, toolDirs []string) ([]byte, error) {
+	var entryPoints []entryPoint
+	engineDll := ``
+	functions := map[string]string{}
+	for _, toolDir := range toolDirs {
+		absDir, err := filepath.Abs(toolDir)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(absDir)
+		config, err := tool_def.ReadConfigXml(filepath.Join(absDir, name+`Config.xml`))
+		if err != nil {
+			return nil, err
+		}
+		function := config.EngineSettings.EngineDllEntryPoint
+		if !token.IsIdentifier(function) || !token.IsExported(function) {
+			return nil, fmt.Errorf(`the EngineDllEntryPoint of %v must be an exported Go identifier but got '%v'`, name, function)
+		}
+		if other, ok := functions[function]; ok {
+			return nil, fmt.Errorf(`%v and %v both use the EngineDllEntryPoint '%v'`, other, name, function)
+		}
+		functions[function] = name
+		if engineDll == `` {
+			engineDll = config.EngineSettings.EngineDll
+		} else if config.EngineSettings.EngineDll != engineDll {
+			return nil, fmt.Errorf(`all tools must use the same EngineDll but %v uses '%v' instead of '%v'`, name, config.EngineSettings.EngineDll, engineDll)
+		}
+		entryPoints = append(entryPoints, entryPoint{tool: name, function: function})
+	}
+	sort.Slice(entryPoints, func(i, j int) bool {
+		return entryPoints[i].function < entryPoints[j].function
+	})
+
+	source := &bytes.Buffer{}
+	fmt.Fprintf(source, "// Code generated by entrygen. DO NOT EDIT.\n\npackage %v\n\n", pkg)
+	source.WriteString("import \"C\"\nimport (\n\t\"github.com/tlarsendataguy/goalteryx/sdk\"\n\t\"unsafe\"\n)\n")
+	for _, entry := range entryPoints {
+		fmt.Fprintf(source, "\n//export %v\n", entry.function)
+		fmt.Fprintf(source, "func %v(toolId C.int, xmlProperties unsafe.Pointer, engineInterface unsafe.Pointer, pluginInterface unsafe.Pointer) C.long {\n", entry.function)
+		fmt.Fprintf(source, "\treturn C.long(sdk.RegisterEntry(%q, int(toolId), xmlProperties, engineInterface, pluginInterface))\n}\n", entry.tool)
+	}
+	return format.Source(source.Bytes())
+}
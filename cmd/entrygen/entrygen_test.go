@@ -0,0 +1,82 @@
+package main
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk/tool_def"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func createTool(t *testing.T, root string, name string, engineDll string, entryPoint string) string {
+	toolDir := filepath.Join(root, name)
+	_ = os.Mkdir(toolDir, 0755)
+	err := tool_def.Generate(tool_def.Tool{EngineDll: engineDll, EntryPoint: entryPoint, Title: name}, toolDir)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	return toolDir
+}
+
+func TestGenerateEntryPoints(t *testing.T) {
+	root, err := ioutil.TempDir(``, ``)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	defer os.RemoveAll(root)
+	second := createTool(t, root, `Second`, `suite.dll`, `SecondEntry`)
+	first := createTool(t, root, `First`, `suite.dll`, `FirstEntry`)
+
+	source, err := generateEntryPoints(`main`, []string{second, first})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	expected := `// Code generated by entrygen. DO NOT EDIT.
+
+package main
+
+import "C"
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"unsafe"
+)
+
+//export FirstEntry
+func FirstEntry(toolId C.int, xmlProperties unsafe.Pointer, engineInterface unsafe.Pointer, pluginInterface unsafe.Pointer) C.long {
+	return C.long(sdk.RegisterEntry("First", int(toolId), xmlProperties, engineInterface, pluginInterface))
+}
+
+//export SecondEntry
+func SecondEntry(toolId C.int, xmlProperties unsafe.Pointer, engineInterface unsafe.Pointer, pluginInterface unsafe.Pointer) C.long {
+	return C.long(sdk.RegisterEntry("Second", int(toolId), xmlProperties, engineInterface, pluginInterface))
+}
+`
+	if string(source) != expected {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, string(source))
+	}
+}
+
+func TestGenerateEntryPointsValidation(t *testing.T) {
+	root, err := ioutil.TempDir(``, ``)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	defer os.RemoveAll(root)
+	first := createTool(t, root, `First`, `suite.dll`, `Entry`)
+	duplicate := createTool(t, root, `Duplicate`, `suite.dll`, `Entry`)
+	otherDll := createTool(t, root, `OtherDll`, `other.dll`, `OtherEntry`)
+	invalid := createTool(t, root, `Invalid`, `suite.dll`, `lower`)
+
+	_, err = generateEntryPoints(`main`, []string{first, duplicate})
+	if err == nil || err.Error() != `First and Duplicate both use the EngineDllEntryPoint 'Entry'` {
+		t.Fatalf(`expected a duplicate error but got %v`, err)
+	}
+	_, err = generateEntryPoints(`main`, []string{first, otherDll})
+	if err == nil || err.Error() != `all tools must use the same EngineDll but OtherDll uses 'other.dll' instead of 'suite.dll'` {
+		t.Fatalf(`expected an EngineDll error but got %v`, err)
+	}
+	_, err = generateEntryPoints(`main`, []string{invalid})
+	if err == nil || err.Error() != `the EngineDllEntryPoint of Invalid must be an exported Go identifier but got 'lower'` {
+		t.Fatalf(`expected an identifier error but got %v`, err)
+	}
+}
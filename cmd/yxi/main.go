@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	settings := packageSettings{}
+	flag.StringVar(&settings.dll, `dll`, ``, `a prebuilt DLL to package instead of building one`)
+	flag.StringVar(&settings.pkg, `pkg`, ``, `the directory of the Go package to build (default: the tool directory)`)
+	flag.StringVar(&settings.cc, `cc`, defaultCrossCompiler, `the C compiler used to cross-compile the DLL on non-Windows hosts`)
+	flag.StringVar(&settings.output, `o`, ``, `the path of the .yxi file (default: <tool name>.yxi)`)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: yxi [flags] <tool directory>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	settings.toolDir = flag.Arg(0)
+
+	output, err := packageTool(settings)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	fmt.Println(output)
+}
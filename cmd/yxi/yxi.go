@@ -0,0 +1,277 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"github.com/tlarsendataguy/goalteryx/sdk/tool_def"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultCrossCompiler = `x86_64-w64-mingw32-gcc`
+
+const exportDirectoryIndex = 0
+
+const exportDirectorySize = 40
+
+var archiveTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type packageSettings struct {
+	toolDir string
+	dll     string
+	pkg     string
+	cc      string
+	output  string
+}
+
+type manifest struct {
+	XMLName  xml.Name     `xml:"Configuration"`
+	MetaInfo manifestInfo `xml:"Properties>MetaInfo"`
+}
+
+type manifestInfo struct {
+	Name         string `xml:"Name"`
+	Description  string `xml:"Description"`
+	ToolVersion  string `xml:"ToolVersion"`
+	CategoryName string `xml:"CategoryName"`
+	Author       string `xml:"Author"`
+	Icon         string `xml:"Icon,omitempty"`
+}
+
+func packageTool(settings packageSettings) (string, error) {
+	toolDir, err := filepath.Abs(settings.toolDir)
+	if err != nil {
+		return ``, err
+	}
+	name := filepath.Base(toolDir)
+	config, err := tool_def.ReadConfigXml(filepath.Join(toolDir, name+`Config.xml`))
+	if err != nil {
+		return ``, err
+	}
+	dllName := config.EngineSettings.EngineDll
+	entryPoint := config.EngineSettings.EngineDllEntryPoint
+	if !strings.EqualFold(filepath.Ext(dllName), `.dll`) || filepath.Base(dllName) != dllName {
+		return ``, fmt.Errorf(`EngineDll must be the file name of a .dll but got '%v'`, dllName)
+	}
+	if entryPoint == `` {
+		return ``, fmt.Errorf(`EngineDllEntryPoint is required`)
+	}
+
+	var dll []byte
+	if settings.dll != `` {
+		if filepath.Base(settings.dll) != dllName {
+			return ``, fmt.Errorf(`the DLL is named '%v' but EngineDll is '%v'`, filepath.Base(settings.dll), dllName)
+		}
+		dll, err = ioutil.ReadFile(settings.dll)
+		if err != nil {
+			return ``, err
+		}
+		exports, err := dllExports(dll)
+		if err != nil {
+			return ``, err
+		}
+		found := false
+		for _, export := range exports {
+			found = found || export == entryPoint
+		}
+		if !found {
+			return ``, fmt.Errorf(`the DLL does not export '%v'`, entryPoint)
+		}
+	} else {
+		pkg := settings.pkg
+		if pkg == `` {
+			pkg = toolDir
+		}
+		err = checkExport(pkg, entryPoint)
+		if err != nil {
+			return ``, err
+		}
+		dll, err = buildDll(pkg, dllName, settings.cc)
+		if err != nil {
+			return ``, err
+		}
+	}
+
+	files := map[string][]byte{
+		name + `/` + dllName: dll,
+	}
+	for _, file := range []string{name + `Config.xml`, config.GuiSettings.Html, config.GuiSettings.Icon} {
+		if file == `` {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(toolDir, file))
+		if err != nil {
+			return ``, err
+		}
+		files[name+`/`+filepath.ToSlash(file)] = content
+	}
+
+	info := manifestInfo{
+		Name:         config.MetaInfo.Name,
+		Description:  config.MetaInfo.Description,
+		ToolVersion:  config.MetaInfo.ToolVersion,
+		CategoryName: config.MetaInfo.CategoryName,
+		Author:       config.MetaInfo.Author,
+	}
+	if config.GuiSettings.Icon != `` {
+		info.Icon = filepath.Base(config.GuiSettings.Icon)
+		files[info.Icon] = files[name+`/`+filepath.ToSlash(config.GuiSettings.Icon)]
+	}
+	manifestXml, err := xml.MarshalIndent(manifest{MetaInfo: info}, ``, `  `)
+	if err != nil {
+		return ``, err
+	}
+	files[`Config.xml`] = append([]byte(xml.Header), manifestXml...)
+
+	output := settings.output
+	if output == `` {
+		output = name + `.yxi`
+	}
+	return output, writeArchive(output, files)
+}
+
+func checkExport(pkg string, entryPoint string) error {
+	sources, err := filepath.Glob(filepath.Join(pkg, `*.go`))
+	if err != nil {
+		return err
+	}
+	for _, source := range sources {
+		content, err := ioutil.ReadFile(source)
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			if strings.TrimSpace(line) == `//export `+entryPoint {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf(`no '//export %v' function was found in %v`, entryPoint, pkg)
+}
+
+func dllExports(dll []byte) ([]string, error) {
+	file, err := pe.NewFile(bytes.NewReader(dll))
+	if err != nil {
+		return nil, fmt.Errorf(`the DLL could not be read: %v`, err.Error())
+	}
+	var directories []pe.DataDirectory
+	switch header := file.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		directories = header.DataDirectory[:minUint32(header.NumberOfRvaAndSizes, uint32(len(header.DataDirectory)))]
+	case *pe.OptionalHeader64:
+		directories = header.DataDirectory[:minUint32(header.NumberOfRvaAndSizes, uint32(len(header.DataDirectory)))]
+	}
+	if len(directories) <= exportDirectoryIndex || directories[exportDirectoryIndex].Size == 0 {
+		return nil, nil
+	}
+
+	directory, err := readDllRva(file, directories[exportDirectoryIndex].VirtualAddress, exportDirectorySize)
+	if err != nil {
+		return nil, err
+	}
+	count := binary.LittleEndian.Uint32(directory[24:28])
+	names, err := readDllRva(file, binary.LittleEndian.Uint32(directory[32:36]), 4*uint64(count))
+	if err != nil {
+		return nil, err
+	}
+	exports := make([]string, count)
+	for index := range exports {
+		name, err := readDllRva(file, binary.LittleEndian.Uint32(names[4*index:]), 1)
+		if err != nil {
+			return nil, err
+		}
+		end := bytes.IndexByte(name, 0)
+		if end == -1 {
+			return nil, fmt.Errorf(`the DLL's export table is corrupt`)
+		}
+		exports[index] = string(name[:end])
+	}
+	return exports, nil
+}
+
+func readDllRva(file *pe.File, rva uint32, length uint64) ([]byte, error) {
+	for _, section := range file.Sections {
+		size := section.VirtualSize
+		if section.Size > size {
+			size = section.Size
+		}
+		if rva < section.VirtualAddress || uint64(rva) >= uint64(section.VirtualAddress)+uint64(size) {
+			continue
+		}
+		data, err := section.Data()
+		if err != nil {
+			return nil, fmt.Errorf(`the DLL could not be read: %v`, err.Error())
+		}
+		offset := uint64(rva - section.VirtualAddress)
+		if offset+length > uint64(len(data)) {
+			return nil, fmt.Errorf(`the DLL's export table is corrupt`)
+		}
+		return data[offset:], nil
+	}
+	return nil, fmt.Errorf(`the DLL's export table is corrupt`)
+}
+
+func minUint32(first uint32, second uint32) uint32 {
+	if first < second {
+		return first
+	}
+	return second
+}
+
+func buildDll(pkg string, dllName string, cc string) ([]byte, error) {
+	buildDir, err := ioutil.TempDir(``, `yxi`)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(buildDir)
+
+	dllPath := filepath.Join(buildDir, dllName)
+	command := exec.Command(`go`, `build`, `-buildmode=c-shared`, `-trimpath`, `-o`, dllPath, `.`)
+	command.Dir = pkg
+	command.Env = append(os.Environ(), `GOOS=windows`, `GOARCH=amd64`, `CGO_ENABLED=1`)
+	if runtime.GOOS != `windows` {
+		command.Env = append(command.Env, `CC=`+cc)
+	}
+	output, err := command.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error building %v: %v\n%v", dllName, err.Error(), string(output))
+	}
+	return ioutil.ReadFile(dllPath)
+}
+
+func writeArchive(path string, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archive := &bytes.Buffer{}
+	writer := zip.NewWriter(archive)
+	for _, name := range names {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: archiveTime}
+		file, err := writer.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(files[name])
+		if err != nil {
+			return err
+		}
+	}
+	err := writer.Close()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, archive.Bytes(), 0644)
+}
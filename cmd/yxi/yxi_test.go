@@ -0,0 +1,203 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"github.com/tlarsendataguy/goalteryx/sdk/tool_def"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testDll(exports ...string) []byte {
+	const exportRva = 0x1000
+	const rawOffset = 0x200
+	namesRva := exportRva + exportDirectorySize
+	ordinalsRva := namesRva + 4*len(exports)
+	stringsRva := ordinalsRva + 2*len(exports)
+
+	exportData := &bytes.Buffer{}
+	directory := make([]byte, exportDirectorySize)
+	binary.LittleEndian.PutUint32(directory[24:], uint32(len(exports)))
+	binary.LittleEndian.PutUint32(directory[32:], uint32(namesRva))
+	binary.LittleEndian.PutUint32(directory[36:], uint32(ordinalsRva))
+	exportData.Write(directory)
+	nameRva := stringsRva
+	for _, export := range exports {
+		_ = binary.Write(exportData, binary.LittleEndian, uint32(nameRva))
+		nameRva += len(export) + 1
+	}
+	for index := range exports {
+		_ = binary.Write(exportData, binary.LittleEndian, uint16(index))
+	}
+	for _, export := range exports {
+		exportData.WriteString(export + "\x00")
+	}
+
+	dll := &bytes.Buffer{}
+	dosHeader := make([]byte, 0x40)
+	copy(dosHeader, `MZ`)
+	binary.LittleEndian.PutUint32(dosHeader[0x3c:], 0x40)
+	dll.Write(dosHeader)
+	dll.WriteString("PE\x00\x00")
+	_ = binary.Write(dll, binary.LittleEndian, pe.FileHeader{
+		Machine:              0x8664,
+		NumberOfSections:     1,
+		SizeOfOptionalHeader: uint16(binary.Size(pe.OptionalHeader64{})),
+		Characteristics:      0x2022,
+	})
+	optionalHeader := pe.OptionalHeader64{
+		Magic:               0x20b,
+		SectionAlignment:    0x1000,
+		FileAlignment:       rawOffset,
+		NumberOfRvaAndSizes: 16,
+	}
+	optionalHeader.DataDirectory[exportDirectoryIndex] = pe.DataDirectory{VirtualAddress: exportRva, Size: uint32(exportData.Len())}
+	_ = binary.Write(dll, binary.LittleEndian, optionalHeader)
+	_ = binary.Write(dll, binary.LittleEndian, pe.SectionHeader32{
+		Name:             [8]uint8{'.', 'e', 'd', 'a', 't', 'a'},
+		VirtualSize:      uint32(exportData.Len()),
+		VirtualAddress:   exportRva,
+		SizeOfRawData:    uint32(exportData.Len()),
+		PointerToRawData: rawOffset,
+	})
+	dll.Write(make([]byte, rawOffset-dll.Len()))
+	dll.Write(exportData.Bytes())
+	return dll.Bytes()
+}
+
+func createToolDir(t *testing.T) (string, string) {
+	root, err := ioutil.TempDir(``, ``)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	toolDir := filepath.Join(root, `MyTool`)
+	_ = os.Mkdir(toolDir, 0755)
+	tool := tool_def.Tool{
+		EngineDll:   `goalteryx.dll`,
+		EntryPoint:  `PluginEntry`,
+		Title:       `My Tool`,
+		Description: `Does things`,
+		Category:    `Go Tools`,
+		Author:      `tlarsendataguy`,
+		Icon:        `icon.png`,
+		Outputs:     []tool_def.Anchor{{Name: `Output`}},
+	}
+	err = tool_def.Generate(tool, toolDir)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	_ = ioutil.WriteFile(filepath.Join(toolDir, `icon.png`), []byte(`icon`), 0644)
+	_ = ioutil.WriteFile(filepath.Join(root, `goalteryx.dll`), testDll(`PluginEntry`), 0644)
+	return root, toolDir
+}
+
+func TestPackageTool(t *testing.T) {
+	root, toolDir := createToolDir(t)
+	defer os.RemoveAll(root)
+
+	output := filepath.Join(root, `MyTool.yxi`)
+	settings := packageSettings{toolDir: toolDir, dll: filepath.Join(root, `goalteryx.dll`), output: output}
+	_, err := packageTool(settings)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	reader, err := zip.OpenReader(output)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	defer reader.Close()
+	var names []string
+	contents := map[string]string{}
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+		opened, _ := file.Open()
+		content, _ := ioutil.ReadAll(opened)
+		_ = opened.Close()
+		contents[file.Name] = string(content)
+	}
+	expectedNames := []string{`Config.xml`, `MyTool/MyTool.html`, `MyTool/MyToolConfig.xml`, `MyTool/goalteryx.dll`, `MyTool/icon.png`, `icon.png`}
+	if !reflect.DeepEqual(expectedNames, names) {
+		t.Fatalf(`expected %v but got %v`, expectedNames, names)
+	}
+	for _, line := range []string{`<Name>My Tool</Name>`, `<ToolVersion>1.0</ToolVersion>`, `<CategoryName>Go Tools</CategoryName>`, `<Icon>icon.png</Icon>`} {
+		if !strings.Contains(contents[`Config.xml`], line) {
+			t.Fatalf("expected manifest to contain %v but got\n%v", line, contents[`Config.xml`])
+		}
+	}
+
+	first, _ := ioutil.ReadFile(output)
+	_, _ = packageTool(settings)
+	second, _ := ioutil.ReadFile(output)
+	if !bytes.Equal(first, second) {
+		t.Fatalf(`expected packaging to be reproducible but the archives differ`)
+	}
+}
+
+func TestPackageToolValidation(t *testing.T) {
+	root, toolDir := createToolDir(t)
+	defer os.RemoveAll(root)
+
+	renamed := filepath.Join(root, `other.dll`)
+	_ = ioutil.WriteFile(renamed, testDll(`PluginEntry`), 0644)
+	_, err := packageTool(packageSettings{toolDir: toolDir, dll: renamed})
+	if err == nil || err.Error() != `the DLL is named 'other.dll' but EngineDll is 'goalteryx.dll'` {
+		t.Fatalf(`expected a DLL name error but got %v`, err)
+	}
+
+	_ = ioutil.WriteFile(filepath.Join(root, `goalteryx.dll`), testDll(`OtherEntry`, `PluginEntryPoint`), 0644)
+	_, err = packageTool(packageSettings{toolDir: toolDir, dll: filepath.Join(root, `goalteryx.dll`)})
+	if err == nil || err.Error() != `the DLL does not export 'PluginEntry'` {
+		t.Fatalf(`expected an export error but got %v`, err)
+	}
+
+	_ = ioutil.WriteFile(filepath.Join(root, `goalteryx.dll`), []byte("MZ\x00PluginEntry\x00"), 0644)
+	_, err = packageTool(packageSettings{toolDir: toolDir, dll: filepath.Join(root, `goalteryx.dll`)})
+	if err == nil || !strings.HasPrefix(err.Error(), `the DLL could not be read`) {
+		t.Fatalf(`expected a read error but got %v`, err)
+	}
+
+	_, err = packageTool(packageSettings{toolDir: toolDir})
+	if err == nil || !strings.Contains(err.Error(), `no '//export PluginEntry' function was found`) {
+		t.Fatalf(`expected a missing export error but got %v`, err)
+	}
+}
+
+func TestCheckExport(t *testing.T) {
+	pkg, err := ioutil.TempDir(``, ``)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	defer os.RemoveAll(pkg)
+	_ = ioutil.WriteFile(filepath.Join(pkg, `entry.go`), []byte("package main\n\n//export PluginEntry\nfunc PluginEntry() {}\n"), 0644)
+
+	if err := checkExport(pkg, `PluginEntry`); err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if err := checkExport(pkg, `Plugin`); err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
+
+func TestDllExports(t *testing.T) {
+	exports, err := dllExports(testDll(`First`, `PluginEntry`))
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if expected := []string{`First`, `PluginEntry`}; !reflect.DeepEqual(expected, exports) {
+		t.Fatalf(`expected %v but got %v`, expected, exports)
+	}
+
+	dll := testDll(`PluginEntry`)
+	binary.LittleEndian.PutUint32(dll[0x200+24:], 1000000)
+	_, err = dllExports(dll)
+	if err == nil || err.Error() != `the DLL's export table is corrupt` {
+		t.Fatalf(`expected a corrupt table error but got %v`, err)
+	}
+}
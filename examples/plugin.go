@@ -1,14 +1,13 @@
 package main
 
 import (
-	"encoding/xml"
 	"fmt"
 	"github.com/tlarsendataguy/goalteryx/sdk"
 	"io/ioutil"
 )
 
 type Configuration struct {
-	Password string
+	Password string `ayx:"Password,password"`
 }
 
 type Plugin struct {
@@ -31,13 +30,8 @@ func (p *Plugin) Init(provider sdk.Provider) {
 		provider.Io().Error(err.Error())
 	}
 	provider.Io().Info(fmt.Sprintf(`temp file content: %v`, string(data)))
-	configBytes := []byte(provider.ToolConfig())
-	err = xml.Unmarshal(configBytes, &p.config)
-	if err != nil {
-		provider.Io().Error(err.Error())
-	}
-	password := provider.Io().DecryptPassword(p.config.Password)
-	provider.Io().Info(fmt.Sprintf(`got password %v`, password))
+	_ = sdk.BindToolConfig(provider, &p.config)
+	provider.Io().Info(fmt.Sprintf(`got password %v`, p.config.Password))
 	p.provider = provider
 	p.output = provider.GetOutputAnchor(`Output`)
 }
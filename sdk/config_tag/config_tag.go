@@ -0,0 +1,101 @@
+package config_tag
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const StructTag = `ayx`
+
+type Tag struct {
+	Name         string
+	DefaultValue string
+	HasDefault   bool
+	Required     bool
+	Enum         []string
+	Password     bool
+}
+
+type Field struct {
+	Index []int
+	Tag   Tag
+	Type  reflect.Type
+}
+
+func Fields(structType reflect.Type) ([]Field, error) {
+	var fields []Field
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if field.PkgPath != `` {
+			continue
+		}
+		tag, skip, err := Parse(field)
+		if err != nil {
+			return nil, err
+		}
+		if skip {
+			continue
+		}
+		valueType := field.Type
+		if valueType.Kind() == reflect.Slice {
+			valueType = valueType.Elem()
+		}
+		if !IsScalarKind(valueType.Kind()) && !(valueType.Kind() == reflect.Struct && field.Type.Kind() == reflect.Struct) {
+			return nil, fmt.Errorf(`struct field '%v' has type %v, which cannot be bound to the tool configuration`, field.Name, field.Type)
+		}
+		if tag.Password && valueType.Kind() != reflect.String {
+			return nil, fmt.Errorf(`struct field '%v' is tagged as a password but is not a string`, field.Name)
+		}
+		fields = append(fields, Field{Index: field.Index, Tag: tag, Type: field.Type})
+	}
+	return fields, nil
+}
+
+func Parse(field reflect.StructField) (Tag, bool, error) {
+	tagValue := field.Tag.Get(StructTag)
+	if tagValue == `-` {
+		return Tag{}, true, nil
+	}
+	parts := strings.Split(tagValue, `,`)
+	tag := Tag{Name: parts[0]}
+	if tag.Name == `` {
+		tag.Name = field.Name
+	}
+	for _, part := range parts[1:] {
+		keyValue := strings.SplitN(part, `=`, 2)
+		key := strings.TrimSpace(keyValue[0])
+		switch {
+		case key == `required` && len(keyValue) == 1:
+			tag.Required = true
+		case key == `password` && len(keyValue) == 1:
+			tag.Password = true
+		case key == `default` && len(keyValue) == 2:
+			tag.DefaultValue = keyValue[1]
+			tag.HasDefault = true
+		case key == `enum` && len(keyValue) == 2:
+			tag.Enum = strings.Split(keyValue[1], `|`)
+		default:
+			return tag, false, fmt.Errorf(`invalid option '%v' in the tag of struct field '%v'`, part, field.Name)
+		}
+	}
+	return tag, false, nil
+}
+
+func IsScalarKind(kind reflect.Kind) bool {
+	return kind == reflect.String || kind == reflect.Bool || IsIntKind(kind) || IsFloatKind(kind)
+}
+
+func IsIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
@@ -0,0 +1,192 @@
+package sdk
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+type RoundingMode int
+
+const (
+	RoundHalfUp RoundingMode = iota
+	RoundHalfEven
+	RoundHalfDown
+	RoundUp
+	RoundDown
+	RoundCeiling
+	RoundFloor
+)
+
+// Decimal is an arbitrary-precision decimal number.  The value is unscaled * 10^-scale.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+var bigTen = big.NewInt(10)
+
+func NewDecimal(unscaled int64, scale int) Decimal {
+	if scale < 0 {
+		value := new(big.Int).Mul(big.NewInt(unscaled), pow10(-scale))
+		return Decimal{unscaled: value, scale: 0}
+	}
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+}
+
+func ParseDecimal(value string) (Decimal, error) {
+	trimmed := strings.TrimSpace(value)
+	digits := trimmed
+	negative := false
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		negative = digits[0] == '-'
+		digits = digits[1:]
+	}
+	integerPart := digits
+	fractionPart := ``
+	if index := strings.IndexByte(digits, '.'); index >= 0 {
+		integerPart = digits[:index]
+		fractionPart = digits[index+1:]
+	}
+	if integerPart == `` && fractionPart == `` {
+		return Decimal{}, fmt.Errorf(`'%v' is not a valid decimal`, value)
+	}
+	for _, part := range []string{integerPart, fractionPart} {
+		for _, char := range part {
+			if char < '0' || char > '9' {
+				return Decimal{}, fmt.Errorf(`'%v' is not a valid decimal`, value)
+			}
+		}
+	}
+	unscaled, _ := new(big.Int).SetString(`0`+integerPart+fractionPart, 10)
+	if negative {
+		unscaled.Neg(unscaled)
+	}
+	return Decimal{unscaled: unscaled, scale: len(fractionPart)}, nil
+}
+
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+func (d Decimal) Sign() int {
+	return d.bigInt().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	left, right := alignScales(d, other)
+	return left.Cmp(right)
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.bigInt()), scale: d.scale}
+}
+
+func (d Decimal) Add(other Decimal) Decimal {
+	left, right := alignScales(d, other)
+	return Decimal{unscaled: left.Add(left, right), scale: maxInt(d.scale, other.scale)}
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	return d.Add(other.Neg())
+}
+
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.bigInt(), other.bigInt()), scale: d.scale + other.scale}
+}
+
+func (d Decimal) Float64() float64 {
+	value, _ := new(big.Float).SetPrec(128).SetString(d.String())
+	result, _ := value.Float64()
+	return result
+}
+
+func (d Decimal) Round(scale int, mode RoundingMode) Decimal {
+	if scale < 0 {
+		scale = 0
+	}
+	if scale >= d.scale {
+		return Decimal{unscaled: new(big.Int).Mul(d.bigInt(), pow10(scale-d.scale)), scale: scale}
+	}
+
+	divisor := pow10(d.scale - scale)
+	quotient, remainder := new(big.Int).QuoRem(d.bigInt(), divisor, new(big.Int))
+	if remainder.Sign() == 0 {
+		return Decimal{unscaled: quotient, scale: scale}
+	}
+
+	sign := d.Sign()
+	doubledRemainder := new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2))
+	halfCmp := doubledRemainder.Cmp(divisor)
+
+	awayFromZero := false
+	switch mode {
+	case RoundHalfUp:
+		awayFromZero = halfCmp >= 0
+	case RoundHalfDown:
+		awayFromZero = halfCmp > 0
+	case RoundHalfEven:
+		awayFromZero = halfCmp > 0 || (halfCmp == 0 && quotient.Bit(0) == 1)
+	case RoundUp:
+		awayFromZero = true
+	case RoundDown:
+		awayFromZero = false
+	case RoundCeiling:
+		awayFromZero = sign > 0
+	case RoundFloor:
+		awayFromZero = sign < 0
+	}
+	if awayFromZero {
+		quotient.Add(quotient, big.NewInt(int64(sign)))
+	}
+	return Decimal{unscaled: quotient, scale: scale}
+}
+
+func (d Decimal) String() string {
+	unscaled := d.bigInt()
+	digits := new(big.Int).Abs(unscaled).String()
+	if d.scale > 0 {
+		if len(digits) <= d.scale {
+			digits = strings.Repeat(`0`, d.scale-len(digits)+1) + digits
+		}
+		point := len(digits) - d.scale
+		digits = digits[:point] + `.` + digits[point:]
+	}
+	if unscaled.Sign() < 0 {
+		return `-` + digits
+	}
+	return digits
+}
+
+func (d Decimal) bigInt() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+func alignScales(left Decimal, right Decimal) (*big.Int, *big.Int) {
+	leftInt := new(big.Int).Set(left.bigInt())
+	rightInt := new(big.Int).Set(right.bigInt())
+	if left.scale > right.scale {
+		rightInt.Mul(rightInt, pow10(left.scale-right.scale))
+	} else if right.scale > left.scale {
+		leftInt.Mul(leftInt, pow10(right.scale-left.scale))
+	}
+	return leftInt, rightInt
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(exponent)), nil)
+}
+
+func maxInt(first int, second int) int {
+	if first > second {
+		return first
+	}
+	return second
+}
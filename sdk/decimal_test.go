@@ -0,0 +1,85 @@
+package sdk_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	cases := map[string]string{
+		`123.45`:                  `123.45`,
+		`-0.05`:                   `-0.05`,
+		`  98.20`:                 `98.20`,
+		`+7`:                      `7`,
+		`.5`:                      `0.5`,
+		`12345678901234567.89`:    `12345678901234567.89`,
+		`-99999999999999999.9999`: `-99999999999999999.9999`,
+	}
+	for input, expected := range cases {
+		value, err := sdk.ParseDecimal(input)
+		if err != nil {
+			t.Fatalf(`expected no error parsing '%v' but got: %v`, input, err.Error())
+		}
+		if actual := value.String(); actual != expected {
+			t.Fatalf(`expected '%v' but got '%v'`, expected, actual)
+		}
+	}
+}
+
+func TestParseInvalidDecimal(t *testing.T) {
+	for _, input := range []string{``, `-`, `.`, `12a`, `1.2.3`, `1e5`} {
+		_, err := sdk.ParseDecimal(input)
+		if err == nil {
+			t.Fatalf(`expected an error parsing '%v' but got none`, input)
+		}
+	}
+}
+
+func TestDecimalRounding(t *testing.T) {
+	type roundCase struct {
+		input    string
+		mode     sdk.RoundingMode
+		expected string
+	}
+	cases := []roundCase{
+		{`2.345`, sdk.RoundHalfUp, `2.35`},
+		{`-2.345`, sdk.RoundHalfUp, `-2.35`},
+		{`2.345`, sdk.RoundHalfDown, `2.34`},
+		{`2.345`, sdk.RoundHalfEven, `2.34`},
+		{`2.355`, sdk.RoundHalfEven, `2.36`},
+		{`2.341`, sdk.RoundUp, `2.35`},
+		{`2.349`, sdk.RoundDown, `2.34`},
+		{`-2.341`, sdk.RoundCeiling, `-2.34`},
+		{`-2.341`, sdk.RoundFloor, `-2.35`},
+		{`2.3`, sdk.RoundHalfUp, `2.30`},
+	}
+	for _, item := range cases {
+		value, _ := sdk.ParseDecimal(item.input)
+		if actual := value.Round(2, item.mode).String(); actual != item.expected {
+			t.Fatalf(`expected %v to round to '%v' but got '%v'`, item.input, item.expected, actual)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	first, _ := sdk.ParseDecimal(`0.1`)
+	second, _ := sdk.ParseDecimal(`0.20`)
+	if sum := first.Add(second).String(); sum != `0.30` {
+		t.Fatalf(`expected '0.30' but got '%v'`, sum)
+	}
+	if diff := first.Sub(second).String(); diff != `-0.10` {
+		t.Fatalf(`expected '-0.10' but got '%v'`, diff)
+	}
+	if product := first.Mul(second).String(); product != `0.020` {
+		t.Fatalf(`expected '0.020' but got '%v'`, product)
+	}
+	if cmp := first.Cmp(second); cmp != -1 {
+		t.Fatalf(`expected -1 but got %v`, cmp)
+	}
+	if value := sdk.NewDecimal(12345, 2).String(); value != `123.45` {
+		t.Fatalf(`expected '123.45' but got '%v'`, value)
+	}
+	if value := (sdk.Decimal{}).String(); value != `0` {
+		t.Fatalf(`expected '0' but got '%v'`, value)
+	}
+}
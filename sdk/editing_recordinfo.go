@@ -141,6 +141,7 @@ func (i *EditingRecordInfo) GenerateOutgoingRecordInfo() *OutgoingRecordInfo {
 		BlobFields:     make(map[string]OutgoingBlobField),
 		BoolFields:     make(map[string]OutgoingBoolField),
 		DateTimeFields: make(map[string]OutgoingDateTimeField),
+		DecimalFields:  make(map[string]OutgoingDecimalField),
 		FloatFields:    make(map[string]OutgoingFloatField),
 		IntFields:      make(map[string]OutgoingIntField),
 		StringFields:   make(map[string]OutgoingStringField),
@@ -173,6 +174,7 @@ func (i *EditingRecordInfo) GenerateOutgoingRecordInfo() *OutgoingRecordInfo {
 		case `FixedDecimal`:
 			outgoing = NewFixedDecimalField(field.Name, field.Source, field.Size, field.Scale)()
 			info.FloatFields[field.Name] = outgoing
+			info.DecimalFields[field.Name] = outgoing
 		case `Date`:
 			outgoing = NewDateField(field.Name, field.Source)()
 			info.DateTimeFields[field.Name] = outgoing
@@ -477,6 +477,69 @@ func TestTruncateNumber(t *testing.T) {
 	}
 }
 
+func TestOutgoingDecimalField(t *testing.T) {
+	editor := &sdk.EditingRecordInfo{}
+	editor.AddFixedDecimalField(`Field1`, ``, 19, 2)
+	info := editor.GenerateOutgoingRecordInfo()
+	field, ok := info.DecimalFields[`Field1`]
+	if !ok {
+		t.Fatalf(`expected a field but got none`)
+	}
+	if currentValue, isNull := field.GetCurrentDecimal(); !currentValue.IsZero() || isNull {
+		t.Fatalf(`expected 0 and not null for a new field but got %v and %v`, currentValue, isNull)
+	}
+	expectedValue, _ := sdk.ParseDecimal(`9999999999999999.99`)
+	err := field.SetDecimal(expectedValue)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if currentValue, isNull := field.GetCurrentDecimal(); currentValue.String() != `9999999999999999.99` || isNull {
+		t.Fatalf(`expected 9999999999999999.99 and not null but got %v and %v`, currentValue, isNull)
+	}
+	field.SetNull()
+	if currentValue, isNull := field.GetCurrentDecimal(); !currentValue.IsZero() || isNull != true {
+		t.Fatalf(`expected 0 and null but got %v and %v`, currentValue, isNull)
+	}
+}
+
+func TestOutgoingDecimalFieldRounding(t *testing.T) {
+	editor := &sdk.EditingRecordInfo{}
+	editor.AddFixedDecimalField(`Field1`, ``, 19, 2)
+	info := editor.GenerateOutgoingRecordInfo()
+	field := info.DecimalFields[`Field1`]
+	value, _ := sdk.ParseDecimal(`2.345`)
+
+	_ = field.SetDecimal(value)
+	if currentValue, _ := field.GetCurrentDecimal(); currentValue.String() != `2.35` {
+		t.Fatalf(`expected 2.35 but got %v`, currentValue)
+	}
+	field.SetRoundingMode(sdk.RoundHalfEven)
+	_ = field.SetDecimal(value)
+	if currentValue, _ := field.GetCurrentDecimal(); currentValue.String() != `2.34` {
+		t.Fatalf(`expected 2.34 but got %v`, currentValue)
+	}
+}
+
+func TestOutgoingDecimalFieldTooLarge(t *testing.T) {
+	editor := &sdk.EditingRecordInfo{}
+	editor.AddFixedDecimalField(`Field1`, ``, 5, 2)
+	info := editor.GenerateOutgoingRecordInfo()
+	field := info.DecimalFields[`Field1`]
+	value, _ := sdk.ParseDecimal(`12.34`)
+	if err := field.SetDecimal(value); err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	value, _ = sdk.ParseDecimal(`123.4`)
+	err := field.SetDecimal(value)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+	if currentValue, _ := field.GetCurrentDecimal(); currentValue.String() != `12.34` {
+		t.Fatalf(`expected the previous value of 12.34 but got %v`, currentValue)
+	}
+	t.Logf(err.Error())
+}
+
 func TestOutgoingDateField(t *testing.T) {
 	editor := &sdk.EditingRecordInfo{}
 	editor.AddDateField(`Field1`, ``)
@@ -8,5 +8,6 @@ type Environment interface {
 	AlteryxInstallDir() string
 	AlteryxLocale() string
 	ToolId() int
+	RecordLimit() int
 	UpdateToolConfig(string)
 }
@@ -42,6 +42,10 @@ func (e *ayxEnvironment) ToolId() int {
 	return int(e.sharedMemory.toolId)
 }
 
+func (e *ayxEnvironment) RecordLimit() int {
+	return int(e.sharedMemory.recordLimit)
+}
+
 func (e *ayxEnvironment) UpdateToolConfig(newConfig string) {
 	sendMessageToEngine(e.sharedMemory, UpdateOutputMetaInfoXml, newConfig)
 	updateConfig(e.sharedMemory, newConfig)
@@ -6,6 +6,7 @@ type testEnvironment struct {
 	updateMode   string
 	workflowDir  string
 	locale       string
+	log          *testMessageLog
 }
 
 func (e *testEnvironment) UpdateOnly() bool {
@@ -36,6 +37,11 @@ func (e *testEnvironment) ToolId() int {
 	return int(e.sharedMemory.toolId)
 }
 
+func (e *testEnvironment) RecordLimit() int {
+	return int(e.sharedMemory.recordLimit)
+}
+
 func (e *testEnvironment) UpdateToolConfig(newConfig string) {
+	e.log.addConfig(newConfig)
 	updateConfig(e.sharedMemory, newConfig)
 }
@@ -12,6 +12,7 @@ type FloatGetter func(Record) (float64, bool)
 type BoolGetter func(Record) (bool, bool)
 type TimeGetter func(Record) (time.Time, bool)
 type StringGetter func(Record) (string, bool)
+type DecimalGetter func(Record) (Decimal, bool)
 
 func bytesToByte(getBytes BytesGetter) IntGetter {
 	return func(record Record) (int, bool) {
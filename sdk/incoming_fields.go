@@ -1,7 +1,9 @@
 package sdk
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -28,6 +30,15 @@ type IncomingFloatField struct {
 	GetValue FloatGetter
 }
 
+type IncomingDecimalField struct {
+	Name     string
+	Type     string
+	Source   string
+	Size     int
+	Scale    int
+	GetValue DecimalGetter
+}
+
 type IncomingBoolField struct {
 	Name     string
 	Type     string
@@ -86,6 +97,14 @@ func truncateAtNullByte(raw []byte) []byte {
 	return raw[:dataLen]
 }
 
+func parseFixedDecimal(raw []byte) (Decimal, error) {
+	valueStr := strings.TrimSpace(string(truncateAtNullByte(raw)))
+	if valueStr == `` {
+		return NewDecimal(0, 0), nil
+	}
+	return ParseDecimal(valueStr)
+}
+
 func generateFixedDecimalField(field IncomingField) IncomingFloatField {
 	getter := func(record Record) (float64, bool) {
 		bytes := field.GetBytes(record)
@@ -104,6 +123,28 @@ func generateFixedDecimalField(field IncomingField) IncomingFloatField {
 	}
 }
 
+func generateDecimalField(field IncomingField) IncomingDecimalField {
+	getter := func(record Record) (Decimal, bool) {
+		bytes := field.GetBytes(record)
+		if bytes[field.Size] == 1 {
+			return Decimal{}, true
+		}
+		value, err := parseFixedDecimal(bytes[:field.Size])
+		if err != nil {
+			panic(fmt.Sprintf(`error reading field '%v': %v`, field.Name, err.Error()))
+		}
+		return value, false
+	}
+	return IncomingDecimalField{
+		Name:     field.Name,
+		Type:     field.Type,
+		Source:   field.Source,
+		Size:     field.Size,
+		Scale:    field.Scale,
+		GetValue: getter,
+	}
+}
+
 func generateBoolField(field IncomingField) IncomingBoolField {
 	getter := func(record Record) (bool, bool) {
 		bytes := field.GetBytes(record)
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	b "github.com/tlarsendataguy/goalteryx/sdk/field_base"
+	"unsafe"
 )
 
 const dateFormat = `2006-01-02`
@@ -21,7 +22,9 @@ type xmlRecordInfo struct {
 }
 
 type IncomingRecordInfo struct {
-	fields []IncomingField
+	fields       []IncomingField
+	fixedSize    int
+	hasVarFields bool
 }
 
 func (i IncomingRecordInfo) NumFields() int {
@@ -42,6 +45,29 @@ func (i IncomingRecordInfo) Fields() []b.FieldBase {
 	return fields
 }
 
+func (i IncomingRecordInfo) RecordSize(record Record) int {
+	if !i.hasVarFields {
+		return i.fixedSize
+	}
+	varSize := *(*uint32)(unsafe.Pointer(uintptr(record) + uintptr(i.fixedSize)))
+	return i.fixedSize + 4 + int(varSize)
+}
+
+func (i IncomingRecordInfo) CopyRecord(record Record) Record {
+	size := i.RecordSize(record)
+	copied := make([]byte, size)
+	copy(copied, ptrToBytes(record, 0, size))
+	return Record(&copied[0])
+}
+
+func (i IncomingRecordInfo) CopyRecords(packet RecordPacket) []Record {
+	var records []Record
+	for packet.Next() {
+		records = append(records, i.CopyRecord(packet.Record()))
+	}
+	return records
+}
+
 func (i IncomingRecordInfo) Clone() *EditingRecordInfo {
 	return &EditingRecordInfo{fields: i.fields}
 }
@@ -86,6 +112,21 @@ func (i IncomingRecordInfo) GetFloatField(name string) (IncomingFloatField, erro
 	return IncomingFloatField{}, fmt.Errorf(`there is no '%v' field in the record`, name)
 }
 
+func (i IncomingRecordInfo) GetDecimalField(name string) (IncomingDecimalField, error) {
+	for _, field := range i.fields {
+		if field.Name != name {
+			continue
+		}
+		switch field.Type {
+		case `FixedDecimal`:
+			return generateDecimalField(field), nil
+		default:
+			return IncomingDecimalField{}, fmt.Errorf(`the '%v' field is not a fixed decimal field, it is '%v'`, name, field.Type)
+		}
+	}
+	return IncomingDecimalField{}, fmt.Errorf(`there is no '%v' field in the record`, name)
+}
+
 func (i IncomingRecordInfo) GetBoolField(name string) (IncomingBoolField, error) {
 	for _, field := range i.fields {
 		if field.Name != name {
@@ -169,11 +210,13 @@ func incomingRecordInfoFromString(config string) (IncomingRecordInfo, error) {
 		return IncomingRecordInfo{}, err
 	}
 	startAt := 0
+	hasVarFields := false
 	for index, field := range metaInfo.RecordInfo.Fields {
 		switch field.Type {
 		case `V_String`, `V_WString`, `Blob`, `SpatialObj`:
 			field.GetBytes = generateGetVarBytes(startAt)
 			startAt += 4
+			hasVarFields = true
 		case `Bool`:
 			field.GetBytes = generateGetFixedBytes(startAt, 1)
 			startAt += 1
@@ -210,5 +253,5 @@ func incomingRecordInfoFromString(config string) (IncomingRecordInfo, error) {
 		}
 		metaInfo.RecordInfo.Fields[index] = field
 	}
-	return IncomingRecordInfo{fields: metaInfo.RecordInfo.Fields}, nil
+	return IncomingRecordInfo{fields: metaInfo.RecordInfo.Fields, fixedSize: startAt, hasVarFields: hasVarFields}, nil
 }
@@ -261,6 +261,44 @@ func TestGetFixedDecimalValue(t *testing.T) {
 	}
 }
 
+func TestGetDecimalValue(t *testing.T) {
+	config := `<RecordInfo>
+	<Field name="Field1" type="Bool"/>
+	<Field name="Field2" type="FixedDecimal" size="19" scale="2" />
+</RecordInfo>`
+	recordInfo, _ := incomingRecordInfoFromString(config)
+	field, err := recordInfo.GetDecimalField(`Field2`)
+	if err != nil {
+		t.Fatalf(`expected no error but got %v`, err.Error())
+	}
+	if field.Size != 19 || field.Scale != 2 {
+		t.Fatalf(`expected size 19 and scale 2 but got %v and %v`, field.Size, field.Scale)
+	}
+
+	record := unsafe.Pointer(&[]byte{2, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 46, 48, 49, 0, 0}[0])
+	value, isNull := field.GetValue(record)
+	if actual := value.String(); actual != `999999999999999.01` {
+		t.Fatalf(`expected '999999999999999.01' but got '%v'`, actual)
+	}
+	if isNull {
+		t.Fatalf(`expected not null but got null`)
+	}
+
+	record = unsafe.Pointer(&[]byte{2, 49, 50, 51, 46, 52, 53, 0, 64, 65, 66, 67, 68, 69, 70, 71, 72, 73, 74, 75, 1}[0])
+	value, isNull = field.GetValue(record)
+	if !value.IsZero() {
+		t.Fatalf(`expected 0 but got %v`, value)
+	}
+	if !isNull {
+		t.Fatalf(`expected null but got not null`)
+	}
+
+	_, err = recordInfo.GetDecimalField(`Field1`)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
+
 func TestGetBoolValue(t *testing.T) {
 	config := `<RecordInfo>
 	<Field name="Field1" type="Bool"/>
@@ -530,3 +568,31 @@ func TestClone(t *testing.T) {
 		t.Fatalf(`expected 2 fields but got %v`, editor.NumFields())
 	}
 }
+
+func TestBindErrors(t *testing.T) {
+	config := `<RecordInfo>
+	<Field name="Field1" type="Int32"/>
+</RecordInfo>`
+	recordInfo, _ := incomingRecordInfoFromString(config)
+
+	type missing struct {
+		Value int `ayx:"Field2"`
+	}
+	_, err := recordInfo.Bind(&missing{})
+	if err == nil {
+		t.Fatalf(`expected an error for a missing field but got none`)
+	}
+
+	type wrongType struct {
+		Value string `ayx:"Field1"`
+	}
+	_, err = recordInfo.Bind(&wrongType{})
+	if err == nil {
+		t.Fatalf(`expected an error for a mismatched type but got none`)
+	}
+
+	_, err = recordInfo.Bind(missing{})
+	if err == nil {
+		t.Fatalf(`expected an error for a non-pointer but got none`)
+	}
+}
@@ -0,0 +1,58 @@
+package sdk
+
+type InputAnchor interface {
+	Name() string
+	Connections() []InputConnection
+	AllClosed() bool
+}
+
+type inputAnchor struct {
+	sharedMemory *goPluginSharedMemory
+	name         string
+	noCache      bool
+}
+
+func (a *inputAnchor) Name() string {
+	return a.name
+}
+
+func (a *inputAnchor) Connections() []InputConnection {
+	connections := []InputConnection{}
+	anchor := findInputAnchor(a.sharedMemory, a.name)
+	if anchor == nil {
+		return connections
+	}
+	for connection := anchor.firstChild; connection != nil; connection = connection.nextConnection {
+		connections = append(connections, newInputConnection(connection, a.noCache))
+	}
+	return connections
+}
+
+func (a *inputAnchor) AllClosed() bool {
+	anchor := findInputAnchor(a.sharedMemory, a.name)
+	if anchor == nil {
+		return true
+	}
+	for connection := anchor.firstChild; connection != nil; connection = connection.nextConnection {
+		if connection.status != Closed {
+			return false
+		}
+	}
+	return true
+}
+
+func findInputAnchor(sharedMemory *goPluginSharedMemory, name string) *goInputAnchorData {
+	for anchor := sharedMemory.inputAnchors; anchor != nil; anchor = anchor.nextAnchor {
+		if utf16PtrToString(anchor.name, utf16PtrLen(anchor.name)) == name {
+			return anchor
+		}
+	}
+	return nil
+}
+
+func newInputConnection(data *goInputConnectionData, noCache bool) InputConnection {
+	if noCache {
+		return &ImpInputConnectionNoCache{data: data}
+	}
+	return &ImpInputConnection{data: data}
+}
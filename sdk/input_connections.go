@@ -2,6 +2,8 @@ package sdk
 
 type InputConnection interface {
 	Name() string
+	ConnectionName() string
+	Anchor() InputAnchor
 	Metadata() IncomingRecordInfo
 	Read() RecordPacket
 	Progress() float64
@@ -18,6 +20,15 @@ func (i *ImpInputConnection) Name() string {
 	return name
 }
 
+func (i *ImpInputConnection) ConnectionName() string {
+	nameLen := utf16PtrLen(i.data.name)
+	return utf16PtrToString(i.data.name, nameLen)
+}
+
+func (i *ImpInputConnection) Anchor() InputAnchor {
+	return &inputAnchor{sharedMemory: i.data.plugin, name: i.Name(), noCache: false}
+}
+
 func (i *ImpInputConnection) Metadata() IncomingRecordInfo {
 	configLen := utf16PtrLen(i.data.metadata)
 	configStr := utf16PtrToString(i.data.metadata, configLen)
@@ -47,6 +58,15 @@ func (i *ImpInputConnectionNoCache) Name() string {
 	return name
 }
 
+func (i *ImpInputConnectionNoCache) ConnectionName() string {
+	nameLen := utf16PtrLen(i.data.name)
+	return utf16PtrToString(i.data.name, nameLen)
+}
+
+func (i *ImpInputConnectionNoCache) Anchor() InputAnchor {
+	return &inputAnchor{sharedMemory: i.data.plugin, name: i.Name(), noCache: true}
+}
+
 func (i *ImpInputConnectionNoCache) Metadata() IncomingRecordInfo {
 	configLen := utf16PtrLen(i.data.metadata)
 	configStr := utf16PtrToString(i.data.metadata, configLen)
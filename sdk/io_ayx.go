@@ -1,9 +1,13 @@
 package sdk
 
-import "github.com/tlarsendataguy/goalteryx/sdk/util"
+import (
+	"context"
+)
 
 type ayxIo struct {
 	sharedMemory *goPluginSharedMemory
+	cancel       context.CancelFunc
+	decryptor    PasswordDecryptor
 }
 
 func (a *ayxIo) Error(message string) {
@@ -19,11 +23,15 @@ func (a *ayxIo) Info(message string) {
 }
 
 func (a *ayxIo) UpdateProgress(progress float64) bool {
-	return sendToolProgressToEngine(a.sharedMemory, progress)
+	keepGoing := sendToolProgressToEngine(a.sharedMemory, progress)
+	if !keepGoing {
+		a.cancel()
+	}
+	return keepGoing
 }
 
 func (a *ayxIo) DecryptPassword(value string) string {
-	password, err := util.Encrypt(value)
+	password, err := a.decryptor.DecryptPassword(value)
 	if err != nil {
 		a.Error(`password could not be decrypted`)
 		return value
@@ -1,46 +1,77 @@
 package sdk
 
 import (
+	"context"
 	"fmt"
-	"math/rand"
+	"io/ioutil"
 	"time"
 )
 
-type testIo struct{}
+type testIo struct {
+	log             *testMessageLog
+	cancel          context.CancelFunc
+	cancelAfter     int
+	progressUpdates int
+	decryptor       PasswordDecryptor
+	tempFileDir     string
+}
 
 func (t *testIo) Error(message string) {
+	t.log.addMessage(Error, message)
 	println(fmt.Sprintf(`ERROR: %v`, message))
 }
 
 func (t *testIo) Warn(message string) {
+	t.log.addMessage(Warning, message)
 	println(fmt.Sprintf(`WARNING: %v`, message))
 }
 
 func (t *testIo) Info(message string) {
+	t.log.addMessage(Info, message)
 	println(fmt.Sprintf(`INFO: %v`, message))
 }
 
 func (t *testIo) UpdateProgress(progress float64) bool {
+	t.log.addProgress(progress)
 	println(fmt.Sprintf(`Progress: %v`, progress))
+	t.progressUpdates++
+	if t.cancelAfter > 0 && t.progressUpdates >= t.cancelAfter {
+		if t.cancel != nil {
+			t.cancel()
+		}
+		return false
+	}
 	return true
 }
 
 func (t *testIo) DecryptPassword(value string) string {
-	return value
+	if t.decryptor == nil {
+		return value
+	}
+	password, err := t.decryptor.DecryptPassword(value)
+	if err != nil {
+		t.Error(`password could not be decrypted`)
+		return value
+	}
+	return password
 }
 
 func (t *testIo) CreateTempFile(ext string) string {
 	now := time.Now().Format(`20060102150405`)
-	source := rand.NewSource(time.Now().Unix())
-	generator := rand.New(source)
-	randNum := generator.Intn(1000)
-	return fmt.Sprintf(`%v-%03d.%v`, now, randNum, ext)
+	file, err := ioutil.TempFile(t.tempFileDir, fmt.Sprintf(`%v-*.%v`, now, ext))
+	if err != nil {
+		panic(fmt.Sprintf(`error creating temp file: %v`, err.Error()))
+	}
+	_ = file.Close()
+	return file.Name()
 }
 
 func (t *testIo) NotifyFileInput(message string) {
+	t.log.addMessage(FileInput, message)
 	println(fmt.Sprintf(`FILE INPUT: %v`, message))
 }
 
 func (t *testIo) NotifyFileOutput(message string) {
+	t.log.addMessage(FileOutput, message)
 	println(fmt.Sprintf(`FILE OUTPUT: %v`, message))
 }
@@ -0,0 +1,106 @@
+package sdk
+
+import "errors"
+
+const lzfHashSize = 1 << 14
+const lzfMaxOffset = 1 << 13
+const lzfMaxLiteral = 32
+const lzfMaxMatch = 264
+
+var errLzfCorrupt = errors.New(`lzf block is corrupt`)
+
+func lzfCompress(input []byte) []byte {
+	output := make([]byte, 0, len(input)+len(input)/lzfMaxLiteral+1)
+	var hashTable [lzfHashSize]int
+	literalStart := 0
+
+	flushLiterals := func(end int) {
+		for literalStart < end {
+			count := end - literalStart
+			if count > lzfMaxLiteral {
+				count = lzfMaxLiteral
+			}
+			output = append(output, byte(count-1))
+			output = append(output, input[literalStart:literalStart+count]...)
+			literalStart += count
+		}
+	}
+
+	position := 0
+	for position+2 < len(input) {
+		hash := (int(input[position])<<16 | int(input[position+1])<<8 | int(input[position+2])) * 2654435761 >> 18 & (lzfHashSize - 1)
+		reference := hashTable[hash] - 1
+		hashTable[hash] = position + 1
+		offset := position - reference - 1
+		if reference < 0 || offset >= lzfMaxOffset ||
+			input[reference] != input[position] || input[reference+1] != input[position+1] || input[reference+2] != input[position+2] {
+			position++
+			continue
+		}
+
+		maxLength := len(input) - position
+		if maxLength > lzfMaxMatch {
+			maxLength = lzfMaxMatch
+		}
+		length := 3
+		for length < maxLength && input[reference+length] == input[position+length] {
+			length++
+		}
+
+		flushLiterals(position)
+		encodedLength := length - 2
+		if encodedLength < 7 {
+			output = append(output, byte(encodedLength<<5|offset>>8))
+		} else {
+			output = append(output, byte(7<<5|offset>>8), byte(encodedLength-7))
+		}
+		output = append(output, byte(offset))
+		position += length
+		literalStart = position
+	}
+	flushLiterals(len(input))
+	return output
+}
+
+func lzfDecompress(input []byte, output []byte) (int, error) {
+	inPosition := 0
+	outPosition := 0
+	for inPosition < len(input) {
+		control := int(input[inPosition])
+		inPosition++
+
+		if control < lzfMaxLiteral {
+			count := control + 1
+			if inPosition+count > len(input) || outPosition+count > len(output) {
+				return 0, errLzfCorrupt
+			}
+			copy(output[outPosition:], input[inPosition:inPosition+count])
+			inPosition += count
+			outPosition += count
+			continue
+		}
+
+		length := control >> 5
+		if length == 7 {
+			if inPosition >= len(input) {
+				return 0, errLzfCorrupt
+			}
+			length += int(input[inPosition])
+			inPosition++
+		}
+		length += 2
+		if inPosition >= len(input) {
+			return 0, errLzfCorrupt
+		}
+		reference := outPosition - (control&0x1f)<<8 - int(input[inPosition]) - 1
+		inPosition++
+		if reference < 0 || outPosition+length > len(output) {
+			return 0, errLzfCorrupt
+		}
+		for index := 0; index < length; index++ {
+			output[outPosition] = output[reference+index]
+			outPosition++
+		}
+	}
+	return outPosition, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"math"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -322,6 +323,7 @@ type outgoingField struct {
 	dateTimeSetter  func(time.Time, *outgoingField) `xml:"-"`
 	dateTimeGetter  func(*outgoingField) time.Time  `xml:"-"`
 	fixedDecimalFmt string                          `xml:"-"`
+	roundingMode    RoundingMode                    `xml:"-"`
 	stringSetter    func(string, *outgoingField)    `xml:"-"`
 	stringGetter    func(*outgoingField) string     `xml:"-"`
 	blobSetter      func([]byte, *outgoingField)    `xml:"-"`
@@ -471,6 +473,35 @@ func setFixedDecimal(value float64, f *outgoingField) {
 	}
 }
 
+func (f *outgoingField) SetDecimal(value Decimal) error {
+	rounded := value.Round(f.Scale, f.roundingMode)
+	valueStr := rounded.String()
+	if length := len(valueStr); length > f.Size {
+		return fmt.Errorf(`value '%v' does not fit in field '%v' with a size of %v and scale of %v`, value, f.Name, f.Size, f.Scale)
+	}
+	copy(f.CurrentValue[:f.Size], valueStr)
+	if length := len(valueStr); length < f.Size {
+		f.CurrentValue[length] = 0
+	}
+	f.nullSetter(0, f)
+	return nil
+}
+
+func (f *outgoingField) GetCurrentDecimal() (Decimal, bool) {
+	if f.nullGetter(f) {
+		return Decimal{}, true
+	}
+	value, err := parseFixedDecimal(f.CurrentValue[:f.Size])
+	if err != nil {
+		panic(fmt.Sprintf(`error reading field '%v': %v`, f.Name, err.Error()))
+	}
+	return value, false
+}
+
+func (f *outgoingField) SetRoundingMode(mode RoundingMode) {
+	f.roundingMode = mode
+}
+
 func (f *outgoingField) GetNull() bool {
 	return f.nullGetter(f)
 }
@@ -663,6 +694,13 @@ type OutgoingFloatField interface {
 	GetCurrentFloat() (float64, bool)
 }
 
+type OutgoingDecimalField interface {
+	NullableField
+	SetDecimal(Decimal) error
+	GetCurrentDecimal() (Decimal, bool)
+	SetRoundingMode(RoundingMode)
+}
+
 type OutgoingDateTimeField interface {
 	NullableField
 	SetDateTime(time.Time)
@@ -687,6 +725,7 @@ func NewOutgoingRecordInfo(fields []NewOutgoingField) (*OutgoingRecordInfo, []st
 		BlobFields:     make(map[string]OutgoingBlobField),
 		BoolFields:     make(map[string]OutgoingBoolField),
 		DateTimeFields: make(map[string]OutgoingDateTimeField),
+		DecimalFields:  make(map[string]OutgoingDecimalField),
 		FloatFields:    make(map[string]OutgoingFloatField),
 		IntFields:      make(map[string]OutgoingIntField),
 		StringFields:   make(map[string]OutgoingStringField),
@@ -704,8 +743,11 @@ func NewOutgoingRecordInfo(fields []NewOutgoingField) (*OutgoingRecordInfo, []st
 			info.BoolFields[field.Name] = field
 		case `Byte`, `Int16`, `Int32`, `Int64`:
 			info.IntFields[field.Name] = field
-		case `Float`, `Double`, `FixedDecimal`:
+		case `Float`, `Double`:
 			info.FloatFields[field.Name] = field
+		case `FixedDecimal`:
+			info.FloatFields[field.Name] = field
+			info.DecimalFields[field.Name] = field
 		case `Date`, `DateTime`, `Time`:
 			info.DateTimeFields[field.Name] = field
 		case `String`, `WString`, `V_String`, `V_WString`:
@@ -722,9 +764,12 @@ type OutgoingRecordInfo struct {
 	BlobFields     map[string]OutgoingBlobField
 	BoolFields     map[string]OutgoingBoolField
 	DateTimeFields map[string]OutgoingDateTimeField
+	DecimalFields  map[string]OutgoingDecimalField
 	FloatFields    map[string]OutgoingFloatField
 	IntFields      map[string]OutgoingIntField
 	StringFields   map[string]OutgoingStringField
+
+	structSetterCache map[reflect.Type][]func(reflect.Value) error
 }
 
 func (i *OutgoingRecordInfo) FixedSize() int {
@@ -814,3 +859,16 @@ func checkName(info *OutgoingRecordInfo, name string) string {
 	}
 	return name
 }
+
+func (i *OutgoingRecordInfo) clone() *OutgoingRecordInfo {
+	fields := make([]NewOutgoingField, len(i.outgoingFields))
+	for index, field := range i.outgoingFields {
+		copied := *field
+		copied.CurrentValue = append([]byte(nil), field.CurrentValue...)
+		fields[index] = func() *outgoingField {
+			return &copied
+		}
+	}
+	info, _ := NewOutgoingRecordInfo(fields)
+	return info
+}
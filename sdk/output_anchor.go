@@ -12,14 +12,19 @@ type OutputAnchor interface {
 	Metadata() *OutgoingRecordInfo
 	Open(info *OutgoingRecordInfo)
 	Write()
+	WriteStruct(value interface{}) error
 	UpdateProgress(float64)
 	Close()
 	NumConnections() int
+	DownstreamClosed() bool
 }
 
 type outputAnchor struct {
-	data     *goOutputAnchorData
-	metaData *OutgoingRecordInfo
+	data           *goOutputAnchorData
+	metaData       *OutgoingRecordInfo
+	recordsWritten int
+	limitReached   bool
+	closedNotified bool
 }
 
 func (a *outputAnchor) Name() string {
@@ -63,6 +68,10 @@ func (a *outputAnchor) Write() {
 	if a.data.isOpen == 0 {
 		panic(fmt.Sprintf(`you are writing to output anchor '%v' before it has been opened; call Open() before writing records`, a.Name()))
 	}
+	if exceedsRecordLimit(a, a.data, a.recordsWritten, &a.limitReached) {
+		return
+	}
+	a.recordsWritten++
 	recordSize := a.metaData.DataSize()
 
 	if recordSize > a.data.recordCacheSize {
@@ -76,10 +85,19 @@ func (a *outputAnchor) Write() {
 	}
 
 	cache := ptrToBytes(a.data.recordCache, a.data.recordCachePosition, int(recordSize))
-	writeCache(cache, a.metaData, a.data)
+	writeCache(cache, a.metaData, a.data.fixedSize)
 	a.data.recordCachePosition += recordSize
 }
 
+func (a *outputAnchor) WriteStruct(value interface{}) error {
+	err := a.metaData.SetFromStruct(value)
+	if err != nil {
+		return err
+	}
+	a.Write()
+	return nil
+}
+
 func (a *outputAnchor) UpdateProgress(progress float64) {
 	sendProgressToAnchor(a.data, progress)
 }
@@ -92,6 +110,10 @@ func (a *outputAnchor) NumConnections() int {
 	return a.data.numConnections()
 }
 
+func (a *outputAnchor) DownstreamClosed() bool {
+	return a.data.downstreamClosed()
+}
+
 func varBytesToCache(varBytes []byte, cache []byte, fixedPosition int, varPosition int) int {
 	varWritten := len(varBytes)
 	varDataLen := uint32(varWritten)
@@ -123,8 +145,11 @@ func varBytesToCache(varBytes []byte, cache []byte, fixedPosition int, varPositi
 }
 
 type outputAnchorNoCache struct {
-	data     *goOutputAnchorData
-	metaData *OutgoingRecordInfo
+	data           *goOutputAnchorData
+	metaData       *OutgoingRecordInfo
+	recordsWritten int
+	limitReached   bool
+	closedNotified bool
 }
 
 func (o *outputAnchorNoCache) Name() string {
@@ -158,6 +183,10 @@ func (o *outputAnchorNoCache) Write() {
 	if o.data.isOpen == 0 {
 		panic(fmt.Sprintf(`you are writing to output anchor '%v' before it has been opened; call Open() before writing records`, o.Name()))
 	}
+	if exceedsRecordLimit(o, o.data, o.recordsWritten, &o.limitReached) {
+		return
+	}
+	o.recordsWritten++
 	recordSize := o.metaData.DataSize()
 
 	if recordSize > o.data.recordCacheSize {
@@ -165,10 +194,19 @@ func (o *outputAnchorNoCache) Write() {
 	}
 
 	cache := ptrToBytes(o.data.recordCache, 0, int(recordSize))
-	writeCache(cache, o.metaData, o.data)
+	writeCache(cache, o.metaData, o.data.fixedSize)
 	callWriteRecord(unsafe.Pointer(o.data))
 }
 
+func (o *outputAnchorNoCache) WriteStruct(value interface{}) error {
+	err := o.metaData.SetFromStruct(value)
+	if err != nil {
+		return err
+	}
+	o.Write()
+	return nil
+}
+
 func (o *outputAnchorNoCache) UpdateProgress(progress float64) {
 	sendProgressToAnchor(o.data, progress)
 }
@@ -181,10 +219,40 @@ func (o *outputAnchorNoCache) NumConnections() int {
 	return o.data.numConnections()
 }
 
-func writeCache(cache []byte, metadata *OutgoingRecordInfo, data *goOutputAnchorData) {
+func (o *outputAnchorNoCache) DownstreamClosed() bool {
+	return o.data.downstreamClosed()
+}
+
+func exceedsRecordLimit(anchor OutputAnchor, data *goOutputAnchorData, recordsWritten int, limitReached *bool) bool {
+	limit := int(data.plugin.recordLimit)
+	if limit < 0 || recordsWritten < limit {
+		return false
+	}
+	if !*limitReached {
+		*limitReached = true
+		if notifier, ok := tools[data.plugin].(RecordLimitNotifier); ok {
+			notifier.OnRecordLimitReached(anchor)
+		}
+	}
+	return true
+}
+
+var toolOutputAnchors = map[*goOutputAnchorData]OutputAnchor{}
+
+func notifyIfDownstreamClosed(anchor OutputAnchor, data *goOutputAnchorData, notified *bool) {
+	if *notified || !data.downstreamClosed() {
+		return
+	}
+	*notified = true
+	if notifier, ok := tools[data.plugin].(DownstreamClosedNotifier); ok {
+		notifier.OnDownstreamClosed(anchor)
+	}
+}
+
+func writeCache(cache []byte, metadata *OutgoingRecordInfo, fixedSize uint32) {
 	recordSize := metadata.DataSize()
 	currentFixedPosition := 0
-	currentVarPosition := int(data.fixedSize) + 4
+	currentVarPosition := int(fixedSize) + 4
 	varLen := 0
 	hasVar := false
 
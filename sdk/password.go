@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"github.com/tlarsendataguy/goalteryx/sdk/util"
+	"strings"
+	"unicode/utf16"
+)
+
+const testPasswordMode = `3`
+const testPasswordKey = 0x5A
+
+var testPasswordHeader = []byte{0x01, 0x00, 0x00, 0x00, 0xD0, 0x8C, 0x9D, 0xDF, 0x01, 0x15, 0xD1, 0x11, 0x8C, 0x7A, 0x00, 0xC0, 0x4F, 0xC2, 0x97, 0xEB}
+
+type PasswordDecryptor interface {
+	DecryptPassword(string) (string, error)
+}
+
+type alteryxPasswordDecryptor struct{}
+
+func (alteryxPasswordDecryptor) DecryptPassword(value string) (string, error) {
+	return util.Encrypt(value)
+}
+
+type TestPasswordDecryptor struct{}
+
+func (TestPasswordDecryptor) DecryptPassword(value string) (string, error) {
+	invalid := fmt.Errorf(`'%v' is not an encrypted password`, value)
+	if !strings.HasPrefix(value, testPasswordMode) {
+		return ``, invalid
+	}
+	blob, err := hex.DecodeString(value[len(testPasswordMode):])
+	if err != nil || len(blob) < len(testPasswordHeader)+4 || !bytes.Equal(blob[:len(testPasswordHeader)], testPasswordHeader) {
+		return ``, invalid
+	}
+	payload := blob[len(testPasswordHeader)+4:]
+	if binary.LittleEndian.Uint32(blob[len(testPasswordHeader):]) != uint32(len(payload)) || len(payload)%2 != 0 {
+		return ``, invalid
+	}
+	chars := make([]uint16, len(payload)/2)
+	for index := range chars {
+		chars[index] = binary.LittleEndian.Uint16([]byte{payload[index*2] ^ testPasswordKey, payload[index*2+1] ^ testPasswordKey})
+	}
+	return string(utf16.Decode(chars)), nil
+}
+
+func EncryptTestPassword(password string) string {
+	chars := utf16.Encode([]rune(password))
+	payload := make([]byte, len(chars)*2)
+	for index, char := range chars {
+		binary.LittleEndian.PutUint16(payload[index*2:], char)
+	}
+	for index := range payload {
+		payload[index] ^= testPasswordKey
+	}
+	blob := append([]byte{}, testPasswordHeader...)
+	blob = append(blob, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(blob[len(testPasswordHeader):], uint32(len(payload)))
+	blob = append(blob, payload...)
+	return testPasswordMode + strings.ToUpper(hex.EncodeToString(blob))
+}
@@ -0,0 +1,33 @@
+package sdk_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"strings"
+	"testing"
+)
+
+func TestTestPasswordDecryptor(t *testing.T) {
+	decryptor := sdk.TestPasswordDecryptor{}
+	for _, password := range []string{``, `hello world`, `pässwörd 🔑`} {
+		encrypted := sdk.EncryptTestPassword(password)
+		if !strings.HasPrefix(encrypted, `301000000D08C9DDF0115D1118C7A00C04FC297EB`) {
+			t.Fatalf(`expected an Alteryx-style encrypted string but got '%v'`, encrypted)
+		}
+		if strings.Contains(encrypted, password) && password != `` {
+			t.Fatalf(`expected the password to be hidden but got '%v'`, encrypted)
+		}
+		decrypted, err := decryptor.DecryptPassword(encrypted)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+		if decrypted != password {
+			t.Fatalf(`expected '%v' but got '%v'`, password, decrypted)
+		}
+	}
+
+	for _, invalid := range []string{`hello`, `301000000D08C9DDF0115`, `3XYZ`, sdk.EncryptTestPassword(`abc`) + `00`} {
+		if _, err := decryptor.DecryptPassword(invalid); err == nil {
+			t.Fatalf(`expected an error for '%v' but got none`, invalid)
+		}
+	}
+}
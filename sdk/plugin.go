@@ -6,3 +6,22 @@ type Plugin interface {
 	OnRecordPacket(InputConnection)
 	OnComplete()
 }
+
+type PluginE interface {
+	Init(Provider) error
+	OnInputConnectionOpened(InputConnection) error
+	OnRecordPacket(InputConnection) error
+	OnComplete() error
+}
+
+type ConnectionCloser interface {
+	OnInputConnectionClosed(InputConnection)
+}
+
+type RecordLimitNotifier interface {
+	OnRecordLimitReached(OutputAnchor)
+}
+
+type DownstreamClosedNotifier interface {
+	OnDownstreamClosed(OutputAnchor)
+}
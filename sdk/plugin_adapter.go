@@ -0,0 +1,62 @@
+package sdk
+
+import "fmt"
+
+type pluginAdapter struct {
+	plugin PluginE
+	data   *goPluginSharedMemory
+	err    error
+}
+
+func asPlugin(plugin interface{}) (Plugin, error) {
+	switch typed := plugin.(type) {
+	case Plugin:
+		return typed, nil
+	case PluginE:
+		return &pluginAdapter{plugin: typed}, nil
+	default:
+		return nil, fmt.Errorf(`%T does not implement sdk.Plugin or sdk.PluginE`, plugin)
+	}
+}
+
+func (a *pluginAdapter) Init(provider Provider) {
+	a.fail(a.plugin.Init(provider))
+}
+
+func (a *pluginAdapter) OnInputConnectionOpened(connection InputConnection) {
+	a.fail(a.plugin.OnInputConnectionOpened(connection))
+}
+
+func (a *pluginAdapter) OnRecordPacket(connection InputConnection) {
+	a.fail(a.plugin.OnRecordPacket(connection))
+}
+
+func (a *pluginAdapter) OnComplete() {
+	a.fail(a.plugin.OnComplete())
+}
+
+func (a *pluginAdapter) OnInputConnectionClosed(connection InputConnection) {
+	if closer, ok := a.plugin.(ConnectionCloser); ok {
+		closer.OnInputConnectionClosed(connection)
+	}
+}
+
+func (a *pluginAdapter) OnRecordLimitReached(anchor OutputAnchor) {
+	if notifier, ok := a.plugin.(RecordLimitNotifier); ok {
+		notifier.OnRecordLimitReached(anchor)
+	}
+}
+
+func (a *pluginAdapter) OnDownstreamClosed(anchor OutputAnchor) {
+	if notifier, ok := a.plugin.(DownstreamClosedNotifier); ok {
+		notifier.OnDownstreamClosed(anchor)
+	}
+}
+
+func (a *pluginAdapter) fail(err error) {
+	if err == nil {
+		return
+	}
+	a.err = err
+	failPlugin(a.data, err.Error())
+}
@@ -0,0 +1,62 @@
+package sdk
+
+import (
+	"encoding/xml"
+)
+
+type SortOrder int
+
+const (
+	Ascending SortOrder = iota
+	Descending
+)
+
+type SortField struct {
+	Field string
+	Order SortOrder
+}
+
+type PresortInfo struct {
+	SortFields   []SortField
+	SelectFields []string
+}
+
+type xmlSortInfo struct {
+	XMLName xml.Name       `xml:"SortInfo"`
+	Fields  []xmlSortField `xml:"Field"`
+}
+
+type xmlSortField struct {
+	Field string `xml:"field,attr"`
+	Order string `xml:"order,attr"`
+}
+
+type xmlFieldFilterList struct {
+	XMLName xml.Name         `xml:"FieldFilterList"`
+	Fields  []xmlFilterField `xml:"Field"`
+}
+
+type xmlFilterField struct {
+	Field string `xml:"field,attr"`
+}
+
+func (p PresortInfo) toXml() string {
+	sortInfo := xmlSortInfo{}
+	for _, field := range p.SortFields {
+		order := `Asc`
+		if field.Order == Descending {
+			order = `Desc`
+		}
+		sortInfo.Fields = append(sortInfo.Fields, xmlSortField{Field: field.Field, Order: order})
+	}
+	sortBytes, _ := xml.Marshal(sortInfo)
+	if len(p.SelectFields) == 0 {
+		return string(sortBytes)
+	}
+	filterList := xmlFieldFilterList{}
+	for _, field := range p.SelectFields {
+		filterList.Fields = append(filterList.Fields, xmlFilterField{Field: field})
+	}
+	filterBytes, _ := xml.Marshal(filterList)
+	return string(sortBytes) + string(filterBytes)
+}
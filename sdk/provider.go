@@ -1,10 +1,14 @@
 package sdk
 
+import "context"
+
 type Provider interface {
 	ToolConfig() string
 	Io() Io
+	GetInputAnchor(string) InputAnchor
 	GetOutputAnchor(string) OutputAnchor
 	Environment() Environment
+	Context() context.Context
 }
 
 type provider struct {
@@ -12,6 +16,7 @@ type provider struct {
 	io            Io
 	environment   Environment
 	outputAnchors map[string]*outputAnchor
+	ctx           context.Context
 }
 
 func (p *provider) ToolConfig() string {
@@ -22,6 +27,10 @@ func (p *provider) Io() Io {
 	return p.io
 }
 
+func (p *provider) GetInputAnchor(name string) InputAnchor {
+	return &inputAnchor{sharedMemory: p.sharedMemory, name: name, noCache: false}
+}
+
 func (p *provider) GetOutputAnchor(name string) OutputAnchor {
 	anchor, ok := p.outputAnchors[name]
 	if ok {
@@ -30,6 +39,7 @@ func (p *provider) GetOutputAnchor(name string) OutputAnchor {
 	anchorData := getOrCreateOutputAnchor(p.sharedMemory, name)
 	anchor = &outputAnchor{data: anchorData}
 	p.outputAnchors[name] = anchor
+	toolOutputAnchors[anchorData] = anchor
 	return anchor
 }
 
@@ -37,11 +47,16 @@ func (p *provider) Environment() Environment {
 	return p.environment
 }
 
+func (p *provider) Context() context.Context {
+	return p.ctx
+}
+
 type providerNoCache struct {
 	sharedMemory  *goPluginSharedMemory
 	io            Io
 	environment   Environment
 	outputAnchors map[string]*outputAnchorNoCache
+	ctx           context.Context
 }
 
 func (p *providerNoCache) ToolConfig() string {
@@ -52,6 +67,10 @@ func (p *providerNoCache) Io() Io {
 	return p.io
 }
 
+func (p *providerNoCache) GetInputAnchor(name string) InputAnchor {
+	return &inputAnchor{sharedMemory: p.sharedMemory, name: name, noCache: true}
+}
+
 func (p *providerNoCache) GetOutputAnchor(name string) OutputAnchor {
 	anchor, ok := p.outputAnchors[name]
 	if ok {
@@ -60,9 +79,14 @@ func (p *providerNoCache) GetOutputAnchor(name string) OutputAnchor {
 	anchorData := getOrCreateOutputAnchor(p.sharedMemory, name)
 	anchor = &outputAnchorNoCache{data: anchorData}
 	p.outputAnchors[name] = anchor
+	toolOutputAnchors[anchorData] = anchor
 	return anchor
 }
 
 func (p *providerNoCache) Environment() Environment {
 	return p.environment
 }
+
+func (p *providerNoCache) Context() context.Context {
+	return p.ctx
+}
@@ -0,0 +1,545 @@
+package sdk
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type NullOrder int
+
+const (
+	NullsFirst NullOrder = iota
+	NullsLast
+)
+
+type SortKey struct {
+	Field      string
+	Order      SortOrder
+	Nulls      NullOrder
+	IgnoreCase bool
+}
+
+type recordComparer func(Record, Record) int
+
+type RecordSorter struct {
+	io       Io
+	metadata IncomingRecordInfo
+	compare  recordComparer
+	options  recordStoreOptions
+	memory   []byte
+	offsets  []int
+	runs     []string
+	added    int
+	err      error
+}
+
+func NewRecordSorter(provider Provider, metadata IncomingRecordInfo, keys []SortKey, options ...RecordStoreOptionSetter) (*RecordSorter, error) {
+	compare, err := generateRecordComparer(metadata, keys)
+	if err != nil {
+		return nil, err
+	}
+	storeOptions := recordStoreOptions{memoryLimit: defaultStoreMemoryLimit, mergeFanIn: defaultMergeFanIn}
+	for _, setter := range options {
+		storeOptions = setter(storeOptions)
+	}
+	if storeOptions.mergeFanIn < 2 {
+		storeOptions.mergeFanIn = 2
+	}
+	sorter := &RecordSorter{
+		io:       provider.Io(),
+		metadata: metadata,
+		compare:  compare,
+		options:  storeOptions,
+	}
+	registerCleanup(provider, func() { _ = sorter.Close() })
+	return sorter, nil
+}
+
+func (s *RecordSorter) Add(record Record) error {
+	if s.err != nil {
+		return s.err
+	}
+	size := s.metadata.RecordSize(record)
+	if len(s.memory) > 0 && len(s.memory)+size > s.options.memoryLimit {
+		s.err = s.spillRun()
+		if s.err != nil {
+			return s.err
+		}
+	}
+	s.offsets = append(s.offsets, len(s.memory))
+	s.memory = append(s.memory, ptrToBytes(record, 0, size)...)
+	s.added++
+	return nil
+}
+
+func (s *RecordSorter) AddPacket(packet RecordPacket) error {
+	for packet.Next() {
+		err := s.Add(packet.Record())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RecordSorter) NumRecords() int {
+	return s.added
+}
+
+func (s *RecordSorter) Spilled() bool {
+	return len(s.runs) > 0
+}
+
+func (s *RecordSorter) Read() RecordPacket {
+	for s.err == nil && len(s.runs) > s.options.mergeFanIn {
+		s.err = s.mergeRuns()
+	}
+	if s.err != nil {
+		return &mergePacket{sorter: s}
+	}
+	sources := make([]sortSource, 0, len(s.runs)+1)
+	for _, run := range s.runs {
+		sources = append(sources, &runSource{path: run, metadata: s.metadata})
+	}
+	sources = append(sources, &memorySource{records: s.sortMemory()})
+	return &mergePacket{sorter: s, sources: sources}
+}
+
+func (s *RecordSorter) Err() error {
+	return s.err
+}
+
+func (s *RecordSorter) Close() error {
+	var err error
+	for _, run := range s.runs {
+		removeErr := os.Remove(run)
+		if removeErr != nil && !os.IsNotExist(removeErr) && err == nil {
+			err = removeErr
+		}
+	}
+	s.runs = nil
+	s.memory = nil
+	s.offsets = nil
+	s.added = 0
+	return err
+}
+
+func (s *RecordSorter) sortMemory() []Record {
+	records := make([]Record, len(s.offsets))
+	for index, offset := range s.offsets {
+		records[index] = Record(&s.memory[offset])
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return s.compare(records[i], records[j]) < 0
+	})
+	return records
+}
+
+func (s *RecordSorter) spillRun() error {
+	records := s.sortMemory()
+	sorted := make([]byte, 0, len(s.memory))
+	for _, record := range records {
+		sorted = append(sorted, ptrToBytes(record, 0, s.metadata.RecordSize(record))...)
+	}
+	path, err := writeSpillFile(s.io, sorted)
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, path)
+	s.memory = s.memory[:0]
+	s.offsets = s.offsets[:0]
+	return nil
+}
+
+func (s *RecordSorter) mergeRuns() error {
+	runs := s.runs[:s.options.mergeFanIn]
+	sources := make([]sortSource, len(runs))
+	for index, run := range runs {
+		sources[index] = &runSource{path: run, metadata: s.metadata}
+	}
+	path := s.io.CreateTempFile(`tmp`)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf(`error merging sorted runs into '%v': %v`, path, err.Error())
+	}
+	stream := bufio.NewWriter(file)
+	packet := &mergePacket{sorter: s, sources: sources}
+	for packet.Next() {
+		record := packet.Record()
+		_, err = stream.Write(ptrToBytes(record, 0, s.metadata.RecordSize(record)))
+		if err != nil {
+			packet.closeSources()
+			break
+		}
+	}
+	if err == nil {
+		err = stream.Flush()
+	}
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = s.err
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return fmt.Errorf(`error merging sorted runs into '%v': %v`, path, err.Error())
+	}
+	for _, run := range runs {
+		_ = os.Remove(run)
+	}
+	s.runs = append([]string{path}, s.runs[len(runs):]...)
+	return nil
+}
+
+type sortSource interface {
+	next() (Record, error)
+	close()
+}
+
+type memorySource struct {
+	records []Record
+	index   int
+}
+
+func (m *memorySource) next() (Record, error) {
+	if m.index >= len(m.records) {
+		return nil, nil
+	}
+	m.index++
+	return m.records[m.index-1], nil
+}
+
+func (m *memorySource) close() {}
+
+type runSource struct {
+	path     string
+	metadata IncomingRecordInfo
+	reader   *spillReader
+}
+
+func (r *runSource) next() (Record, error) {
+	if r.reader == nil {
+		reader, err := openSpillReader(r.path, r.metadata)
+		if err != nil {
+			return nil, err
+		}
+		r.reader = reader
+	}
+	return r.reader.next()
+}
+
+func (r *runSource) close() {
+	if r.reader != nil {
+		r.reader.close()
+	}
+}
+
+type mergeEntry struct {
+	source int
+	record Record
+}
+
+type mergeHeap struct {
+	entries []mergeEntry
+	compare recordComparer
+}
+
+func (h *mergeHeap) Len() int {
+	return len(h.entries)
+}
+
+func (h *mergeHeap) Less(i, j int) bool {
+	compared := h.compare(h.entries[i].record, h.entries[j].record)
+	if compared == 0 {
+		return h.entries[i].source < h.entries[j].source
+	}
+	return compared < 0
+}
+
+func (h *mergeHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+}
+
+func (h *mergeHeap) Push(value interface{}) {
+	h.entries = append(h.entries, value.(mergeEntry))
+}
+
+func (h *mergeHeap) Pop() interface{} {
+	last := h.entries[len(h.entries)-1]
+	h.entries = h.entries[:len(h.entries)-1]
+	return last
+}
+
+type mergePacket struct {
+	sorter  *RecordSorter
+	sources []sortSource
+	heap    *mergeHeap
+	current *mergeEntry
+}
+
+func (p *mergePacket) Next() bool {
+	if p.heap == nil {
+		p.heap = &mergeHeap{compare: p.sorter.compare}
+		for index := range p.sources {
+			if !p.advance(index) {
+				return false
+			}
+		}
+		heap.Init(p.heap)
+	} else if p.current != nil && !p.advance(p.current.source) {
+		return false
+	}
+	p.current = nil
+	if p.heap.Len() == 0 {
+		return false
+	}
+	entry := heap.Pop(p.heap).(mergeEntry)
+	p.current = &entry
+	return true
+}
+
+func (p *mergePacket) advance(source int) bool {
+	record, err := p.sources[source].next()
+	if err != nil {
+		p.sorter.err = err
+		p.closeSources()
+		return false
+	}
+	if record == nil {
+		p.sources[source].close()
+		return true
+	}
+	heap.Push(p.heap, mergeEntry{source: source, record: record})
+	return true
+}
+
+func (p *mergePacket) closeSources() {
+	for _, source := range p.sources {
+		source.close()
+	}
+	p.heap.entries = nil
+	p.current = nil
+}
+
+func (p *mergePacket) Record() Record {
+	if p.current == nil {
+		return nil
+	}
+	return p.current.record
+}
+
+func generateRecordComparer(metadata IncomingRecordInfo, keys []SortKey) (recordComparer, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf(`at least one sort key is required`)
+	}
+	comparers := make([]recordComparer, len(keys))
+	for index, key := range keys {
+		compare, err := generateKeyComparer(metadata, key)
+		if err != nil {
+			return nil, err
+		}
+		comparers[index] = compare
+	}
+	return func(first Record, second Record) int {
+		for _, compare := range comparers {
+			if compared := compare(first, second); compared != 0 {
+				return compared
+			}
+		}
+		return 0
+	}, nil
+}
+
+type keyComparer func(Record, Record) (compared int, firstNull bool, secondNull bool)
+
+func generateKeyComparer(metadata IncomingRecordInfo, key SortKey) (recordComparer, error) {
+	var compareValues keyComparer
+	fieldType := ``
+	for _, field := range metadata.fields {
+		if field.Name == key.Field {
+			fieldType = field.Type
+			break
+		}
+	}
+
+	switch fieldType {
+	case ``:
+		return nil, fmt.Errorf(`there is no '%v' field in the record`, key.Field)
+	case `Bool`:
+		field, _ := metadata.GetBoolField(key.Field)
+		compareValues = func(first Record, second Record) (int, bool, bool) {
+			firstValue, firstNull := field.GetValue(first)
+			secondValue, secondNull := field.GetValue(second)
+			return compareBools(firstValue, secondValue), firstNull, secondNull
+		}
+	case `Byte`, `Int16`, `Int32`, `Int64`:
+		field, _ := metadata.GetIntField(key.Field)
+		compareValues = func(first Record, second Record) (int, bool, bool) {
+			firstValue, firstNull := field.GetValue(first)
+			secondValue, secondNull := field.GetValue(second)
+			return compareInts(firstValue, secondValue), firstNull, secondNull
+		}
+	case `Float`, `Double`:
+		field, _ := metadata.GetFloatField(key.Field)
+		compareValues = func(first Record, second Record) (int, bool, bool) {
+			firstValue, firstNull := field.GetValue(first)
+			secondValue, secondNull := field.GetValue(second)
+			return compareFloats(firstValue, secondValue), firstNull, secondNull
+		}
+	case `FixedDecimal`:
+		field, _ := metadata.GetDecimalField(key.Field)
+		compareValues = func(first Record, second Record) (int, bool, bool) {
+			firstValue, firstNull := field.GetValue(first)
+			secondValue, secondNull := field.GetValue(second)
+			if firstNull || secondNull {
+				return 0, firstNull, secondNull
+			}
+			return firstValue.Cmp(secondValue), false, false
+		}
+	case `String`, `WString`, `V_String`, `V_WString`:
+		field, _ := metadata.GetStringField(key.Field)
+		compareStrings := strings.Compare
+		if key.IgnoreCase {
+			compareStrings = compareStringsIgnoreCase
+		}
+		compareValues = func(first Record, second Record) (int, bool, bool) {
+			firstValue, firstNull := field.GetValue(first)
+			secondValue, secondNull := field.GetValue(second)
+			return compareStrings(firstValue, secondValue), firstNull, secondNull
+		}
+	case `Date`, `DateTime`, `Time`:
+		field, _ := metadata.GetTimeField(key.Field)
+		compareValues = func(first Record, second Record) (int, bool, bool) {
+			firstValue, firstNull := field.GetValue(first)
+			secondValue, secondNull := field.GetValue(second)
+			return compareTimes(firstValue, secondValue), firstNull, secondNull
+		}
+	case `Blob`, `SpatialObj`:
+		field, _ := metadata.GetBlobField(key.Field)
+		compareValues = func(first Record, second Record) (int, bool, bool) {
+			firstValue := field.GetValue(first)
+			secondValue := field.GetValue(second)
+			return bytes.Compare(firstValue, secondValue), firstValue == nil, secondValue == nil
+		}
+	default:
+		return nil, fmt.Errorf(`the '%v' field has type '%v', which cannot be sorted`, key.Field, fieldType)
+	}
+
+	return func(first Record, second Record) int {
+		compared, firstNull, secondNull := compareValues(first, second)
+		if firstNull || secondNull {
+			return compareNulls(firstNull, secondNull, key.Nulls)
+		}
+		if key.Order == Descending {
+			return -compared
+		}
+		return compared
+	}, nil
+}
+
+func compareNulls(firstNull bool, secondNull bool, nulls NullOrder) int {
+	if firstNull == secondNull {
+		return 0
+	}
+	if firstNull == (nulls == NullsFirst) {
+		return -1
+	}
+	return 1
+}
+
+func compareBools(first bool, second bool) int {
+	if first == second {
+		return 0
+	}
+	if !first {
+		return -1
+	}
+	return 1
+}
+
+func compareInts(first int, second int) int {
+	if first < second {
+		return -1
+	}
+	if first > second {
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(first float64, second float64) int {
+	firstNaN, secondNaN := math.IsNaN(first), math.IsNaN(second)
+	if firstNaN || secondNaN {
+		return compareNulls(firstNaN, secondNaN, NullsLast)
+	}
+	if first < second {
+		return -1
+	}
+	if first > second {
+		return 1
+	}
+	return 0
+}
+
+func compareStringsIgnoreCase(first string, second string) int {
+	for first != `` && second != `` {
+		firstRune, firstSize := utf8.DecodeRuneInString(first)
+		secondRune, secondSize := utf8.DecodeRuneInString(second)
+		if compared := compareInts(int(unicode.ToLower(firstRune)), int(unicode.ToLower(secondRune))); compared != 0 {
+			return compared
+		}
+		first, second = first[firstSize:], second[secondSize:]
+	}
+	return compareInts(len(first), len(second))
+}
+
+func compareTimes(first time.Time, second time.Time) int {
+	if first.Before(second) {
+		return -1
+	}
+	if first.After(second) {
+		return 1
+	}
+	return 0
+}
+
+func compareSortValues(first interface{}, second interface{}) int {
+	if first == nil || second == nil {
+		return compareNulls(first == nil, second == nil, NullsFirst)
+	}
+	switch value := first.(type) {
+	case bool:
+		return compareBools(value, second.(bool))
+	case int:
+		return compareInts(value, second.(int))
+	case float64:
+		if other, ok := second.(Decimal); ok {
+			return compareFloats(value, other.Float64())
+		}
+		return compareFloats(value, second.(float64))
+	case Decimal:
+		if other, ok := second.(Decimal); ok {
+			return value.Cmp(other)
+		}
+		return compareFloats(value.Float64(), second.(float64))
+	case string:
+		return strings.Compare(value, second.(string))
+	case time.Time:
+		return compareTimes(value, second.(time.Time))
+	case []byte:
+		return bytes.Compare(value, second.([]byte))
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,198 @@
+package sdk_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type SortingTool struct {
+	provider    sdk.Provider
+	output      sdk.OutputAnchor
+	keys        []sdk.SortKey
+	memoryLimit int
+	mergeFanIn  int
+	tempDir     string
+	metadata    sdk.IncomingRecordInfo
+	sorter      *sdk.RecordSorter
+	spilled     bool
+	runFiles    int
+}
+
+func (s *SortingTool) Init(provider sdk.Provider) {
+	s.provider = provider
+	s.output = provider.GetOutputAnchor(`Output`)
+}
+
+func (s *SortingTool) OnInputConnectionOpened(connection sdk.InputConnection) {
+	s.metadata = connection.Metadata()
+	options := []sdk.RecordStoreOptionSetter{sdk.StoreMemoryLimit(s.memoryLimit)}
+	if s.mergeFanIn > 0 {
+		options = append(options, sdk.SortMergeFanIn(s.mergeFanIn))
+	}
+	var err error
+	s.sorter, err = sdk.NewRecordSorter(s.provider, s.metadata, s.keys, options...)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s *SortingTool) OnRecordPacket(connection sdk.InputConnection) {
+	err := s.sorter.AddPacket(connection.Read())
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s *SortingTool) OnComplete() {
+	s.spilled = s.sorter.Spilled()
+	info := s.metadata.Clone().GenerateOutgoingRecordInfo()
+	s.output.Open(info)
+	packet := s.sorter.Read()
+	for packet.Next() {
+		info.CopyFrom(packet.Record())
+		s.output.Write()
+	}
+	if s.tempDir != `` {
+		files, _ := filepath.Glob(filepath.Join(s.tempDir, `*`))
+		s.runFiles = len(files)
+	}
+	if s.sorter.Err() != nil {
+		panic(s.sorter.Err().Error())
+	}
+}
+
+var sortTestFields = []sdk.NewOutgoingField{
+	sdk.NewInt32Field(`Id`, `source`),
+	sdk.NewV_WStringField(`Name`, `source`, 100),
+	sdk.NewInt32Field(`Value`, `source`),
+	sdk.NewFixedDecimalField(`Amount`, `source`, 19, 2),
+}
+
+var sortTestRows = [][]interface{}{
+	{1, `banana`, 2, `10.5`},
+	{2, `Apple`, nil, `-3.25`},
+	{3, `cherry`, 1, nil},
+	{4, `apple`, 2, `10.50`},
+	{5, nil, 1, `7`},
+	{6, `Banana`, nil, `0.01`},
+}
+
+func runSortTest(t *testing.T, keys []sdk.SortKey, memoryLimit int, mergeFanIn int) (*SortingTool, *sdk.RecordCollector) {
+	implementation := &SortingTool{keys: keys, memoryLimit: memoryLimit, mergeFanIn: mergeFanIn}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInputData(`Input`, sortTestFields, sortTestRows)
+	runner.SimulateLifecycle()
+	return implementation, collector
+}
+
+func TestRecordSorterKeys(t *testing.T) {
+	tests := []struct {
+		keys     []sdk.SortKey
+		expected []interface{}
+	}{
+		{[]sdk.SortKey{{Field: `Value`}}, []interface{}{2, 6, 3, 5, 1, 4}},
+		{[]sdk.SortKey{{Field: `Value`, Order: sdk.Descending, Nulls: sdk.NullsLast}}, []interface{}{1, 4, 3, 5, 2, 6}},
+		{[]sdk.SortKey{{Field: `Name`}}, []interface{}{5, 2, 6, 4, 1, 3}},
+		{[]sdk.SortKey{{Field: `Name`, IgnoreCase: true, Nulls: sdk.NullsLast}}, []interface{}{2, 4, 1, 6, 3, 5}},
+		{[]sdk.SortKey{{Field: `Amount`, Order: sdk.Descending}, {Field: `Id`, Order: sdk.Descending}}, []interface{}{3, 4, 1, 5, 6, 2}},
+	}
+	for _, memoryLimit := range []int{1024 * 1024, 1} {
+		for index, test := range tests {
+			implementation, collector := runSortTest(t, test.keys, memoryLimit, 0)
+			if !reflect.DeepEqual(test.expected, collector.Data[`Id`]) {
+				t.Fatalf(`test %v with memory limit %v: expected %v but got %v`, index, memoryLimit, test.expected, collector.Data[`Id`])
+			}
+			_, collector = runSortTest(t, test.keys, memoryLimit, 2)
+			if !reflect.DeepEqual(test.expected, collector.Data[`Id`]) {
+				t.Fatalf(`test %v with memory limit %v and a fan-in of 2: expected %v but got %v`, index, memoryLimit, test.expected, collector.Data[`Id`])
+			}
+			if implementation.spilled != (memoryLimit == 1) {
+				t.Fatalf(`test %v with memory limit %v: expected spilled to be %v`, index, memoryLimit, memoryLimit == 1)
+			}
+		}
+	}
+}
+
+func TestRecordSorterInvalidKey(t *testing.T) {
+	implementation := &TestImplementation{}
+	sdk.RegisterToolTest(implementation, 1, ``)
+	runner := sdk.RegisterToolTest(&PassThroughTool{}, 2, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInputData(`Input`, sortTestFields, sortTestRows)
+	runner.SimulateLifecycle()
+
+	_, err := sdk.NewRecordSorter(implementation.Provider, collector.Config, []sdk.SortKey{{Field: `Missing`}})
+	if err == nil || err.Error() != `there is no 'Missing' field in the record` {
+		t.Fatalf(`expected a missing field error but got %v`, err)
+	}
+	_, err = sdk.NewRecordSorter(implementation.Provider, collector.Config, nil)
+	if err == nil {
+		t.Fatalf(`expected an error without sort keys but got none`)
+	}
+}
+
+func TestRecordSorterManyRuns(t *testing.T) {
+	generator := rand.New(rand.NewSource(42))
+	rows := make([][]interface{}, 5000)
+	for index := range rows {
+		rows[index] = []interface{}{index, strings.Repeat(`x`, generator.Intn(20)), generator.Intn(100), nil}
+	}
+	tempDir, err := ioutil.TempDir(``, `record_sorter`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+	implementation := &SortingTool{keys: []sdk.SortKey{{Field: `Value`}}, memoryLimit: 4096, mergeFanIn: 4, tempDir: tempDir}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.TempFileDir(tempDir))
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInputData(`Input`, sortTestFields, rows)
+	runner.SimulateLifecycle()
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i][2].(int) < rows[j][2].(int)
+	})
+	expected := make([]interface{}, len(rows))
+	for index, row := range rows {
+		expected[index] = row[0]
+	}
+	if !implementation.spilled {
+		t.Fatalf(`expected the sorter to spill to disk`)
+	}
+	if implementation.runFiles > 4 {
+		t.Fatalf(`expected runs to be merged down to at most 4 files but got %v`, implementation.runFiles)
+	}
+	if !reflect.DeepEqual(expected, collector.Data[`Id`]) {
+		t.Fatalf(`expected records to be sorted by Value and then by input order`)
+	}
+}
+
+func TestRecordSorterNaN(t *testing.T) {
+	fields := []sdk.NewOutgoingField{sdk.NewInt32Field(`Id`, `source`), sdk.NewDoubleField(`Value`, `source`)}
+	rows := [][]interface{}{{1, math.NaN()}, {2, 3.5}, {3, nil}, {4, math.Inf(-1)}, {5, math.NaN()}, {6, -1.0}}
+	tests := []struct {
+		keys     []sdk.SortKey
+		expected []interface{}
+	}{
+		{[]sdk.SortKey{{Field: `Value`}}, []interface{}{3, 4, 6, 2, 1, 5}},
+		{[]sdk.SortKey{{Field: `Value`, Order: sdk.Descending, Nulls: sdk.NullsLast}}, []interface{}{1, 5, 2, 6, 4, 3}},
+	}
+	for index, test := range tests {
+		implementation := &SortingTool{keys: test.keys, memoryLimit: 1}
+		runner := sdk.RegisterToolTest(implementation, 1, ``)
+		collector := runner.CaptureOutgoingAnchor(`Output`)
+		runner.ConnectInputData(`Input`, fields, rows)
+		runner.SimulateLifecycle()
+		if !reflect.DeepEqual(test.expected, collector.Data[`Id`]) {
+			t.Fatalf(`test %v: expected %v but got %v`, index, test.expected, collector.Data[`Id`])
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package sdk
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+const defaultStoreMemoryLimit = 64 * 1024 * 1024
+
+const defaultMergeFanIn = 64
+
+type recordStoreOptions struct {
+	memoryLimit int
+	mergeFanIn  int
+}
+
+type RecordStoreOptionSetter func(recordStoreOptions) recordStoreOptions
+
+func StoreMemoryLimit(bytes int) RecordStoreOptionSetter {
+	return func(options recordStoreOptions) recordStoreOptions {
+		options.memoryLimit = bytes
+		return options
+	}
+}
+
+func SortMergeFanIn(files int) RecordStoreOptionSetter {
+	return func(options recordStoreOptions) recordStoreOptions {
+		options.mergeFanIn = files
+		return options
+	}
+}
+
+type RecordStore struct {
+	io         Io
+	metadata   IncomingRecordInfo
+	options    recordStoreOptions
+	memory     []byte
+	files      []string
+	numRecords int
+	err        error
+}
+
+func NewRecordStore(provider Provider, metadata IncomingRecordInfo, options ...RecordStoreOptionSetter) *RecordStore {
+	storeOptions := recordStoreOptions{memoryLimit: defaultStoreMemoryLimit}
+	for _, setter := range options {
+		storeOptions = setter(storeOptions)
+	}
+	store := &RecordStore{
+		io:       provider.Io(),
+		metadata: metadata,
+		options:  storeOptions,
+	}
+	registerCleanup(provider, func() { _ = store.Close() })
+	return store
+}
+
+func (s *RecordStore) Add(record Record) error {
+	if s.err != nil {
+		return s.err
+	}
+	size := s.metadata.RecordSize(record)
+	if len(s.memory) > 0 && len(s.memory)+size > s.options.memoryLimit {
+		s.err = s.spill()
+		if s.err != nil {
+			return s.err
+		}
+	}
+	s.memory = append(s.memory, ptrToBytes(record, 0, size)...)
+	s.numRecords++
+	return nil
+}
+
+func (s *RecordStore) AddPacket(packet RecordPacket) error {
+	for packet.Next() {
+		err := s.Add(packet.Record())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RecordStore) NumRecords() int {
+	return s.numRecords
+}
+
+func (s *RecordStore) Spilled() bool {
+	return len(s.files) > 0
+}
+
+func (s *RecordStore) Read() RecordPacket {
+	return &storePacket{store: s}
+}
+
+func (s *RecordStore) Err() error {
+	return s.err
+}
+
+func (s *RecordStore) Close() error {
+	var err error
+	for _, file := range s.files {
+		removeErr := os.Remove(file)
+		if removeErr != nil && !os.IsNotExist(removeErr) && err == nil {
+			err = removeErr
+		}
+	}
+	s.files = nil
+	s.memory = nil
+	s.numRecords = 0
+	return err
+}
+
+func (s *RecordStore) spill() error {
+	path, err := writeSpillFile(s.io, s.memory)
+	if err != nil {
+		return err
+	}
+	s.files = append(s.files, path)
+	s.memory = s.memory[:0]
+	return nil
+}
+
+func writeSpillFile(toolIo Io, records []byte) (string, error) {
+	path := toolIo.CreateTempFile(`tmp`)
+	err := ioutil.WriteFile(path, records, 0600)
+	if err != nil {
+		_ = os.Remove(path)
+		return ``, fmt.Errorf(`error spilling records to '%v': %v`, path, err.Error())
+	}
+	return path, nil
+}
+
+type spillReader struct {
+	metadata IncomingRecordInfo
+	file     *os.File
+	stream   *bufio.Reader
+	record   []byte
+}
+
+func openSpillReader(path string, metadata IncomingRecordInfo) (*spillReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf(`error reading spilled records: %v`, err.Error())
+	}
+	return &spillReader{metadata: metadata, file: file, stream: bufio.NewReader(file)}, nil
+}
+
+func (r *spillReader) next() (Record, error) {
+	headerSize := r.metadata.fixedSize
+	if r.metadata.hasVarFields {
+		headerSize += 4
+	}
+	r.grow(headerSize)
+	_, err := io.ReadFull(r.stream, r.record)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf(`error reading spilled records: %v`, err.Error())
+	}
+	size := r.metadata.RecordSize(Record(&r.record[0]))
+	if size > headerSize {
+		r.grow(size)
+		_, err = io.ReadFull(r.stream, r.record[headerSize:])
+		if err != nil {
+			return nil, fmt.Errorf(`error reading spilled records: %v`, err.Error())
+		}
+	}
+	return Record(&r.record[0]), nil
+}
+
+func (r *spillReader) grow(size int) {
+	if size > cap(r.record) {
+		grown := make([]byte, size, size*2)
+		copy(grown, r.record)
+		r.record = grown
+	}
+	r.record = r.record[:size]
+}
+
+func (r *spillReader) close() {
+	_ = r.file.Close()
+}
+
+type storePacket struct {
+	store          *RecordStore
+	fileIndex      int
+	reader         *spillReader
+	memoryPosition int
+	current        Record
+}
+
+func (p *storePacket) Next() bool {
+	p.current = nil
+	for p.fileIndex < len(p.store.files) {
+		if p.reader == nil {
+			reader, err := openSpillReader(p.store.files[p.fileIndex], p.store.metadata)
+			if err != nil {
+				p.store.err = err
+				return false
+			}
+			p.reader = reader
+		}
+		record, err := p.reader.next()
+		if err != nil {
+			p.store.err = err
+			p.reader.close()
+			p.reader = nil
+			return false
+		}
+		if record != nil {
+			p.current = record
+			return true
+		}
+		p.reader.close()
+		p.reader = nil
+		p.fileIndex++
+	}
+	if p.memoryPosition >= len(p.store.memory) {
+		return false
+	}
+	p.current = Record(&p.store.memory[p.memoryPosition])
+	p.memoryPosition += p.store.metadata.RecordSize(p.current)
+	return true
+}
+
+func (p *storePacket) Record() Record {
+	return p.current
+}
@@ -0,0 +1,107 @@
+package sdk_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type SpillingTool struct {
+	provider    sdk.Provider
+	output      sdk.OutputAnchor
+	memoryLimit int
+	metadata    sdk.IncomingRecordInfo
+	store       *sdk.RecordStore
+	tempDir     string
+	tempFiles   []string
+}
+
+func (s *SpillingTool) Init(provider sdk.Provider) {
+	s.provider = provider
+	s.output = provider.GetOutputAnchor(`Output`)
+}
+
+func (s *SpillingTool) OnInputConnectionOpened(connection sdk.InputConnection) {
+	s.metadata = connection.Metadata()
+	s.store = sdk.NewRecordStore(s.provider, s.metadata, sdk.StoreMemoryLimit(s.memoryLimit))
+}
+
+func (s *SpillingTool) OnRecordPacket(connection sdk.InputConnection) {
+	err := s.store.AddPacket(connection.Read())
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s *SpillingTool) OnComplete() {
+	s.tempFiles, _ = filepath.Glob(filepath.Join(s.tempDir, `*`))
+	info := s.metadata.Clone().GenerateOutgoingRecordInfo()
+	s.output.Open(info)
+	for pass := 0; pass < 2; pass++ {
+		packet := s.store.Read()
+		for packet.Next() {
+			info.CopyFrom(packet.Record())
+			s.output.Write()
+		}
+	}
+	if s.store.Err() != nil {
+		panic(s.store.Err().Error())
+	}
+}
+
+func recordStoreTest(t *testing.T, memoryLimit int, noCache bool) *SpillingTool {
+	tempDir, err := ioutil.TempDir(``, `record_store`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+	implementation := &SpillingTool{memoryLimit: memoryLimit, tempDir: tempDir}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.NoCache(noCache), sdk.TempFileDir(tempDir))
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	runner = sdk.RegisterToolTest(&PassThroughTool{}, 2, ``, sdk.NoCache(noCache))
+	expected := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if implementation.store.NumRecords() != 0 {
+		t.Fatalf(`expected the store to be closed after OnComplete but it has %v records`, implementation.store.NumRecords())
+	}
+	for name, values := range expected.Data {
+		if expectedValues := append(append([]interface{}{}, values...), values...); !reflect.DeepEqual(expectedValues, collector.Data[name]) {
+			t.Fatalf(`expected %v for %v but got %v`, expectedValues, name, collector.Data[name])
+		}
+	}
+	for _, file := range implementation.tempFiles {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Fatalf(`expected '%v' to be removed but it still exists`, file)
+		}
+	}
+	return implementation
+}
+
+func TestRecordStoreInMemory(t *testing.T) {
+	implementation := recordStoreTest(t, 1024*1024, false)
+	if len(implementation.tempFiles) != 0 {
+		t.Fatalf(`expected no temp files but got %v`, implementation.tempFiles)
+	}
+}
+
+func TestRecordStoreSpillsToDisk(t *testing.T) {
+	implementation := recordStoreTest(t, 200, false)
+	if len(implementation.tempFiles) < 2 {
+		t.Fatalf(`expected records to be spilled to multiple temp files but got %v`, implementation.tempFiles)
+	}
+}
+
+func TestRecordStoreSpillsToDiskNoCache(t *testing.T) {
+	implementation := recordStoreTest(t, 1, true)
+	if len(implementation.tempFiles) != 3 {
+		t.Fatalf(`expected 3 temp files but got %v`, implementation.tempFiles)
+	}
+}
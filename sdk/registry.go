@@ -0,0 +1,41 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"unsafe"
+)
+
+type PluginFactory func() interface{}
+
+type registeredTool struct {
+	factory PluginFactory
+	options []ToolOptionSetter
+}
+
+var registry = map[string]registeredTool{}
+
+func Register(name string, factory PluginFactory, optionSetters ...ToolOptionSetter) {
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf(`a tool named '%v' is already registered`, name))
+	}
+	registry[name] = registeredTool{factory: factory, options: optionSetters}
+}
+
+func RegisteredTools() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func RegisterEntry(name string, toolId int, xmlProperties unsafe.Pointer, engineInterface unsafe.Pointer, pluginInterface unsafe.Pointer) int {
+	tool, ok := registry[name]
+	if !ok {
+		sendEngineMessage(engineInterface, toolId, Error, fmt.Sprintf(`no tool is registered as '%v'; the name passed to sdk.Register must be the name of the tool's directory`, name))
+		return 0
+	}
+	return RegisterTool(tool.factory(), toolId, xmlProperties, engineInterface, pluginInterface, tool.options...)
+}
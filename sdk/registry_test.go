@@ -0,0 +1,36 @@
+package sdk_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"sort"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	sdk.Register(`RegistryFirst`, func() interface{} { return &TestImplementation{} })
+	sdk.Register(`RegistrySecond`, func() interface{} { return &errorTester{} }, sdk.ToolNoCache())
+
+	names := sdk.RegisteredTools()
+	for _, name := range []string{`RegistryFirst`, `RegistrySecond`} {
+		found := false
+		for _, registered := range names {
+			found = found || registered == name
+		}
+		if !found {
+			t.Fatalf(`expected %v to be registered but got %v`, name, names)
+		}
+	}
+	if !sort.StringsAreSorted(names) {
+		t.Fatalf(`expected sorted names but got %v`, names)
+	}
+}
+
+func TestRegistryDuplicate(t *testing.T) {
+	sdk.Register(`RegistryDuplicate`, func() interface{} { return &TestImplementation{} })
+	defer func() {
+		if recovered := recover(); recovered != `a tool named 'RegistryDuplicate' is already registered` {
+			t.Fatalf(`expected a duplicate panic but got %v`, recovered)
+		}
+	}()
+	sdk.Register(`RegistryDuplicate`, func() interface{} { return &TestImplementation{} })
+}
@@ -25,6 +25,8 @@ utf16char empty[1] = {0};
 **             isOpen (char)
 **             ii (struct IncomingInterface*)
 **             nextConnection (struct OutputConn*)
+**             isBrowseEverywhere (char)
+**             isClosedDownstream (char)
 **         nextAnchor (struct OutputAnchor*)
 **         fixedSize (uint32_t)
 **         hasVarFields (char)
@@ -50,7 +52,13 @@ utf16char empty[1] = {0};
 **             recordCache (char *)
 **             recordCachePosition (uint32_t)
 **             recordCacheSize (uint32_t)
+**             name (utf16char *)
 **         nextAnchor (struct InputAnchor*)
+**     presorts (struct Presort*)
+**         anchor (utf16char *)
+**         sortInfo (utf16char *)
+**         nextPresort (struct Presort*)
+**     browseEverywhere (char)
 */
 
 struct PluginInterface* generatePluginInterface(){
@@ -61,20 +69,24 @@ struct IncomingConnectionInterface* generateIncomingConnectionInterface(){
     return malloc(sizeof(struct IncomingConnectionInterface));
 }
 
-void callPiAddIncomingConnection(struct PluginSharedMemory *handle, utf16char * name, struct IncomingConnectionInterface *ii){
-    PI_AddIncomingConnection(handle, name, empty, ii);
+void callPiAddIncomingConnection(struct PluginSharedMemory *handle, utf16char * name, utf16char * connectionName, struct IncomingConnectionInterface *ii){
+    PI_AddIncomingConnection(handle, name, connectionName, ii);
 }
 
-void callPiAddIncomingConnectionNoCache(struct PluginSharedMemory *handle, utf16char * name, struct IncomingConnectionInterface *ii){
-    PI_AddIncomingConnectionNoCache(handle, name, empty, ii);
+void callPiAddIncomingConnectionNoCache(struct PluginSharedMemory *handle, utf16char * name, utf16char * connectionName, struct IncomingConnectionInterface *ii){
+    PI_AddIncomingConnectionNoCache(handle, name, connectionName, ii);
 }
 
 void callPiAddOutgoingConnection(struct PluginSharedMemory *handle, utf16char * name, struct IncomingConnectionInterface *ii){
     PI_AddOutgoingConnection(handle, name, ii);
 }
 
-void simulateInputLifecycle(struct PluginInterface *pluginInterface) {
-    pluginInterface->pPI_PushAllRecords(pluginInterface->handle, 0);
+void simulateInputLifecycle(struct PluginInterface *pluginInterface, int64_t recordLimit) {
+    pluginInterface->pPI_PushAllRecords(pluginInterface->handle, recordLimit);
+    pluginInterface->pPI_Close(pluginInterface->handle, 0);
+}
+
+void closePlugin(struct PluginInterface *pluginInterface) {
     pluginInterface->pPI_Close(pluginInterface->handle, 0);
 }
 
@@ -125,6 +137,10 @@ struct PluginSharedMemory* initializePluginToZero(uint32_t nToolID, utf16char *
     plugin->totalInputConnections = 0;
     plugin->closedInputConnections = 0;
     plugin->inputAnchors = NULL;
+    plugin->presorts = NULL;
+    plugin->browseEverywhere = 1;
+    plugin->recordLimit = -1;
+    plugin->hasFailed = 0;
 
     r_pluginInterface->handle = plugin;
     r_pluginInterface->pPI_Close = &PI_Close;
@@ -150,18 +166,23 @@ void* configurePluginNoCache(uint32_t nToolID, utf16char * pXmlProperties, struc
     return plugin;
 }
 
-void openConn(struct OutputConn* conn, utf16char* metadata) {
+char openConn(struct OutputConn* conn, utf16char* metadata) {
     long result = conn->ii->pII_Init(conn->ii->handle, metadata);
     if (result == 1) {
         conn->isOpen = 1;
+        return 0;
     }
+    conn->isClosedDownstream = 1;
+    return 1;
 }
 
-void appendOutgoingConnection(struct OutputAnchor* anchor, struct IncomingConnectionInterface* ii) {
+struct OutputConn* appendOutgoingConnection(struct OutputAnchor* anchor, struct IncomingConnectionInterface* ii) {
     struct OutputConn* conn = malloc(sizeof(struct OutputConn));
     conn->isOpen = 0;
     conn->ii = ii;
     conn->nextConnection = NULL;
+    conn->isBrowseEverywhere = 0;
+    conn->isClosedDownstream = 0;
 
     if (NULL == anchor->firstChild) {
         anchor->firstChild = conn;
@@ -176,7 +197,7 @@ void appendOutgoingConnection(struct OutputAnchor* anchor, struct IncomingConnec
     if (anchor->isOpen == 1) {
         openConn(conn, anchor->metadata);
     }
-    return;
+    return conn;
 }
 
 void openOutgoingAnchor(struct OutputAnchor *anchor, utf16char * config) {
@@ -187,19 +208,29 @@ void openOutgoingAnchor(struct OutputAnchor *anchor, utf16char * config) {
     if (anchor->plugin->engine != NULL && anchor->browseEverywhereId > 0) {
         struct EngineInterface* engine = anchor->plugin->engine;
         struct IncomingConnectionInterface* ii = engine->pBrowseEverywhereGetII(engine->handle, anchor->browseEverywhereId, anchor->plugin->toolId, anchor->name);
-        appendOutgoingConnection(anchor, ii);
+        if (ii != NULL) {
+            struct OutputConn* conn = appendOutgoingConnection(anchor, ii);
+            conn->isBrowseEverywhere = 1;
+        }
     }
 
     anchor->isOpen = 1;
+    char closed = 0;
     struct OutputConn * conn = anchor->firstChild;
     while (NULL != conn) {
-        openConn(conn, config);
+        closed |= openConn(conn, config);
         conn = conn->nextConnection;
     }
+    if (closed == 1) {
+        goOnDownstreamClosed(anchor);
+    }
 }
 
 void PI_Close(void * handle, bool bHasErrors) {
-    // do nothing
+    struct PluginSharedMemory *plugin = (struct PluginSharedMemory*)handle;
+    goOnClose(plugin);
+    freeAllAnchors(plugin);
+    free(plugin);
 }
 
 void closeOutputAnchor(struct OutputAnchor *anchor) {
@@ -234,6 +265,8 @@ void freeAllInputAnchors(struct InputAnchor *anchor) {
         connection = anchor->firstChild;
         while (connection != NULL) {
             nextConnection = connection->nextConnection;
+            free(connection->metadata);
+            free(connection->name);
             free(connection);
             connection = nextConnection;
         }
@@ -269,20 +302,40 @@ void freeAllOutputAnchors(struct OutputAnchor *anchor) {
     }
 }
 
-void complete(struct PluginSharedMemory *plugin) {
-    goOnComplete(plugin);
+void freeAllPresorts(struct Presort *presort) {
+    struct Presort *nextPresort;
+
+    while (presort != NULL) {
+        nextPresort = presort->nextPresort;
+        free(presort->anchor);
+        free(presort->sortInfo);
+        free(presort);
+        presort = nextPresort;
+    }
+}
+
+void freeAllAnchors(struct PluginSharedMemory *plugin) {
+    freeAllPresorts(plugin->presorts);
+    plugin->presorts = NULL;
     freeAllInputAnchors(plugin->inputAnchors);
-    closeAllOutputAnchors(plugin->outputAnchors);
+    plugin->inputAnchors = NULL;
     freeAllOutputAnchors(plugin->outputAnchors);
+    plugin->outputAnchors = NULL;
+}
+
+long complete(struct PluginSharedMemory *plugin) {
+    long result = goOnComplete(plugin);
+    closeAllOutputAnchors(plugin->outputAnchors);
+    freeAllAnchors(plugin);
     sendMessage(plugin->engine, plugin->toolId, STATUS_Complete, empty);
     //free(plugin->toolConfig);
-    free(plugin);
+    return result;
 }
 
 long PI_PushAllRecords(void * handle, int64_t nRecordLimit){
     struct PluginSharedMemory *plugin = (struct PluginSharedMemory*)handle;
-    complete(plugin);
-    return 1;
+    plugin->recordLimit = nRecordLimit;
+    return complete(plugin);
 }
 
 struct InputAnchor* createInputAnchor(utf16char* name) {
@@ -345,6 +398,15 @@ struct InputConnection* initializeIncomingConnectionToZero(void * handle, utf16c
     connection->recordCachePosition = 0;
     connection->recordCacheSize = 0;
     connection->status = 1;
+    connection->acceptLimit = -1;
+    connection->acceptedRecords = 0;
+
+    if (NULL == pIncomingConnectionName) {
+        pIncomingConnectionName = empty;
+    }
+    uint32_t nameLength = (getLenFromUtf16Ptr(pIncomingConnectionName) + 1) * 2;
+    connection->name = malloc(nameLength);
+    memcpy(connection->name, pIncomingConnectionName, nameLength);
 
     if (anchor->firstChild == NULL) {
         anchor->firstChild = connection;
@@ -360,6 +422,39 @@ struct InputConnection* initializeIncomingConnectionToZero(void * handle, utf16c
     return connection;
 }
 
+void appendPresort(struct PluginSharedMemory* plugin, utf16char * anchor, utf16char * sortInfo) {
+    struct Presort* presort = malloc(sizeof(struct Presort));
+    presort->anchor = anchor;
+    presort->sortInfo = sortInfo;
+    presort->nextPresort = plugin->presorts;
+    plugin->presorts = presort;
+}
+
+void applyPresort(struct PluginSharedMemory* plugin, utf16char * anchor, struct IncomingConnectionInterface *r_IncConnInt) {
+    if (NULL == plugin->engine || NULL == plugin->engine->pPreSort) {
+        return;
+    }
+    struct Presort* presort = plugin->presorts;
+    while (presort != NULL && !isUtf16Equal(anchor, presort->anchor)) {
+        presort = presort->nextPresort;
+    }
+    if (NULL == presort) {
+        return;
+    }
+
+    // the engine pushes sorted records into the original interface for the rest of the run, so it is never freed
+    struct IncomingConnectionInterface *original = malloc(sizeof(struct IncomingConnectionInterface));
+    memcpy(original, r_IncConnInt, sizeof(struct IncomingConnectionInterface));
+    struct IncomingConnectionInterface *presorted = NULL;
+    struct PreSortConnectionInterface *presortConnection = NULL;
+    plugin->engine->pPreSort(plugin->engine->handle, plugin->toolId, presort->sortInfo, original, &presorted, &presortConnection);
+    if (NULL == presorted) {
+        free(original);
+        return;
+    }
+    memcpy(r_IncConnInt, presorted, sizeof(struct IncomingConnectionInterface));
+}
+
 long PI_AddIncomingConnection(void * handle, utf16char * pIncomingConnectionType, utf16char * pIncomingConnectionName, struct IncomingConnectionInterface *r_IncConnInt) {
     struct InputConnection* connection = initializeIncomingConnectionToZero(handle, pIncomingConnectionType, pIncomingConnectionName);
 
@@ -370,6 +465,7 @@ long PI_AddIncomingConnection(void * handle, utf16char * pIncomingConnectionType
     r_IncConnInt->pII_Close = &II_Close;
     r_IncConnInt->pII_Free = &II_Free;
 
+    applyPresort(connection->plugin, pIncomingConnectionType, r_IncConnInt);
     return 1;
 }
 
@@ -383,6 +479,7 @@ long PI_AddIncomingConnectionNoCache(void * handle, utf16char * pIncomingConnect
     r_IncConnInt->pII_Close = &II_CloseNoCache;
     r_IncConnInt->pII_Free = &II_Free;
 
+    applyPresort(connection->plugin, pIncomingConnectionType, r_IncConnInt);
     return 1;
 }
 
@@ -418,7 +515,7 @@ struct OutputAnchor* createOutgoingAnchor(utf16char* name) {
 struct OutputAnchor* appendOutgoingAnchor(struct PluginSharedMemory* plugin, utf16char * name) {
     struct OutputAnchor* anchor = createOutgoingAnchor(name);
     anchor->plugin = plugin;
-    if (plugin->engine != NULL) {
+    if (plugin->engine != NULL && plugin->browseEverywhere == 1) {
         anchor->browseEverywhereId = plugin->engine->pBrowseEverywhereReserveAnchor(plugin->engine->handle, plugin->toolId);
     }
 
@@ -453,8 +550,7 @@ long II_Init(void * handle, utf16char * pXmlRecordMetaInfo) {
     memcpy(input->metadata, pXmlRecordMetaInfo, length);
 
     input->status = 2;
-    goOnInputConnectionOpened(input);
-    return 1;
+    return goOnInputConnectionOpened(input);
 }
 
 uint32_t uint32FromRecordPosition(char * record, uint32_t position) {
@@ -462,8 +558,15 @@ uint32_t uint32FromRecordPosition(char * record, uint32_t position) {
     return *value;
 }
 
+bool acceptLimitReached(struct InputConnection *input) {
+    return input->acceptLimit >= 0 && input->acceptedRecords >= input->acceptLimit;
+}
+
 long II_PushRecord(void * handle, char * pRecord) {
     struct InputConnection *input = (struct InputConnection*)handle;
+    if (input->plugin->hasFailed == 1 || acceptLimitReached(input)) {
+        return 0;
+    }
     input->status = 3;
     uint32_t totalSize = input->fixedSize;
     if (input->hasVarFields == 1) {
@@ -495,17 +598,27 @@ long II_PushRecord(void * handle, char * pRecord) {
         input->recordCachePosition = 0;
     }
 
+    if (input->plugin->hasFailed == 1) {
+        return 0;
+    }
     memcpy(input->recordCache+input->recordCachePosition, pRecord, totalSize);
     input->recordCachePosition += totalSize;
-    return 1;
+    input->acceptedRecords++;
+    return !acceptLimitReached(input);
 }
 
 long II_PushRecordNoCache(void * handle, char * pRecord) {
     struct InputConnection *input = (struct InputConnection*)handle;
+    if (input->plugin->hasFailed == 1 || acceptLimitReached(input)) {
+        return 0;
+    }
     input->status = 3;
     input->recordCache = pRecord;
-    goOnRecordPacketNoCache(handle);
-    return 1;
+    if (goOnRecordPacketNoCache(handle) == 0) {
+        return 0;
+    }
+    input->acceptedRecords++;
+    return !acceptLimitReached(input);
 }
 
 void II_UpdateProgress(void * handle, double dPercent) {
@@ -522,8 +635,11 @@ void II_Close(void * handle) {
     plugin->closedInputConnections++;
 
     free(input->recordCache);
-    free(input->metadata);
+    input->recordCache = NULL;
+    input->recordCachePosition = 0;
+    input->recordCacheSize = 0;
     input->status = 4;
+    goOnInputConnectionClosed(input);
 
     if (plugin->totalInputConnections != plugin->closedInputConnections) {
         return;
@@ -537,6 +653,7 @@ void II_CloseNoCache(void * handle) {
 
     struct PluginSharedMemory *plugin = input->plugin;
     plugin->closedInputConnections++;
+    goOnInputConnectionClosedNoCache(input);
 
     if (plugin->totalInputConnections != plugin->closedInputConnections) {
         return;
@@ -619,6 +736,7 @@ void callWriteRecord(struct OutputAnchor *anchor) {
     }
     char *record = anchor->recordCache;
     uint32_t written = 0;
+    char closed = 0;
     conn = anchor->firstChild;
     while (conn != NULL) {
         if (conn->isOpen == 0) {
@@ -629,9 +747,14 @@ void callWriteRecord(struct OutputAnchor *anchor) {
         if (result == 0) {
             conn->ii->pII_Close(conn->ii->handle);
             conn->isOpen = 0;
+            conn->isClosedDownstream = 1;
+            closed = 1;
         }
         conn = conn->nextConnection;
     }
+    if (closed == 1) {
+        goOnDownstreamClosed(anchor);
+    }
 
     written += anchor->fixedSize;
     if (anchor->hasVarFields == 1) {
@@ -655,6 +778,7 @@ void callWriteRecords(struct OutputAnchor *anchor) {
     }
     char *record;
     uint32_t written = 0;
+    char closed = 0;
     while (written < anchor->recordCachePosition) {
         conn = anchor->firstChild;
         record = &anchor->recordCache[written];
@@ -667,6 +791,8 @@ void callWriteRecords(struct OutputAnchor *anchor) {
             if (result == 0) {
                 conn->ii->pII_Close(conn->ii->handle);
                 conn->isOpen = 0;
+                conn->isClosedDownstream = 1;
+                closed = 1;
             }
             conn = conn->nextConnection;
         }
@@ -682,8 +808,25 @@ void callWriteRecords(struct OutputAnchor *anchor) {
     utf16char msg[128];
     formatRecordCountString(msg, sizeof(msg), anchor->name, anchor->recordCount, anchor->totalDataSize);
     sendMessage(anchor->plugin->engine, anchor->plugin->toolId, STATUS_RecordCountString, &msg[0]);
+    if (closed == 1) {
+        goOnDownstreamClosed(anchor);
+    }
 }
 
 void* allocateCache(int size) {
     return malloc(size);
-}
\ No newline at end of file
+}
+
+struct IncomingConnectionInterface* testEngineBrowseEverywhereGetII(void * handle, unsigned nReservationId, int nToolId, utf16char * strOutputName) {
+    return (struct IncomingConnectionInterface*)goTestEngineBrowseEverywhereGetII(handle, nReservationId, nToolId, strOutputName);
+}
+
+struct EngineInterface* generateTestEngine() {
+    struct EngineInterface* engine = calloc(1, sizeof(struct EngineInterface));
+    engine->sizeof_EngineInterface = sizeof(struct EngineInterface);
+    engine->handle = engine;
+    engine->pOutputMessage = &goTestEngineOutputMessage;
+    engine->pBrowseEverywhereReserveAnchor = &goTestEngineBrowseEverywhereReserveAnchor;
+    engine->pBrowseEverywhereGetII = &testEngineBrowseEverywhereGetII;
+    return engine;
+}
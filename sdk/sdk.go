@@ -5,7 +5,10 @@ package sdk
 */
 import "C"
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"runtime/debug"
 	"unicode/utf16"
 	"unsafe"
 )
@@ -23,6 +26,10 @@ type goPluginSharedMemory struct {
 	totalInputConnections  uint32
 	closedInputConnections uint32
 	inputAnchors           *goInputAnchorData
+	presorts               unsafe.Pointer
+	browseEverywhere       byte
+	recordLimit            int64
+	hasFailed              byte
 }
 
 type goOutputAnchorData struct {
@@ -52,25 +59,34 @@ func (g *goOutputAnchorData) reallocateCache(size uint32) {
 
 func (g *goOutputAnchorData) numConnections() int {
 	total := 0
-	if g.firstChild == nil {
-		return total
+	for child := g.firstChild; child != nil; child = child.nextConnection {
+		if child.isBrowseEverywhere == 0 {
+			total++
+		}
 	}
-	child := g.firstChild
-	total++
-	for child.nextConnection != nil {
+	return total
+}
+
+func (g *goOutputAnchorData) downstreamClosed() bool {
+	total := 0
+	for child := g.firstChild; child != nil; child = child.nextConnection {
+		if child.isBrowseEverywhere == 1 {
+			continue
+		}
+		if child.isClosedDownstream == 0 {
+			return false
+		}
 		total++
-		child = child.nextConnection
 	}
-	if g.browseEverywhereId > 0 {
-		total--
-	}
-	return total
+	return total > 0
 }
 
 type goOutputConnectionData struct {
-	isOpen         byte
-	ii             unsafe.Pointer
-	nextConnection *goOutputConnectionData
+	isOpen             byte
+	ii                 unsafe.Pointer
+	nextConnection     *goOutputConnectionData
+	isBrowseEverywhere byte
+	isClosedDownstream byte
 }
 
 type goInputAnchorData struct {
@@ -101,9 +117,14 @@ type goInputConnectionData struct {
 	recordCache         unsafe.Pointer
 	recordCachePosition uint32
 	recordCacheSize     uint32
+	name                unsafe.Pointer
+	acceptLimit         int64
+	acceptedRecords     int64
 }
 
 var tools = map[*goPluginSharedMemory]Plugin{}
+var recoveringTools = map[*goPluginSharedMemory]bool{}
+var toolCleanups = map[*goPluginSharedMemory][]func(){}
 
 func utf16PtrToString(utf16Ptr unsafe.Pointer, len int) string {
 	var utf16Slice []uint16
@@ -143,12 +164,20 @@ func stringToUtf16Ptr(value string) *C.utf16char {
 	return (*C.utf16char)(byteData)
 }
 
-func simulateInputLifecycle(pluginInterface unsafe.Pointer) {
-	C.simulateInputLifecycle((*C.struct_PluginInterface)(pluginInterface))
+func simulateInputLifecycle(pluginInterface unsafe.Pointer, recordLimit int) {
+	C.simulateInputLifecycle((*C.struct_PluginInterface)(pluginInterface), C.int64_t(recordLimit))
+}
+
+func closePlugin(pluginInterface unsafe.Pointer) {
+	C.closePlugin((*C.struct_PluginInterface)(pluginInterface))
 }
 
 func sendMessageToEngine(data *goPluginSharedMemory, status MessageStatus, message string) {
-	C.sendMessage((*C.struct_EngineInterface)(data.engine), (C.int)(data.toolId), (C.int)(status), (*C.utf16char)(stringToUtf16Ptr(message)))
+	sendEngineMessage(data.engine, int(data.toolId), status, message)
+}
+
+func sendEngineMessage(engine unsafe.Pointer, toolId int, status MessageStatus, message string) {
+	C.sendMessage((*C.struct_EngineInterface)(engine), (C.int)(toolId), (C.int)(status), (*C.utf16char)(stringToUtf16Ptr(message)))
 }
 
 func sendToolProgressToEngine(data *goPluginSharedMemory, progress float64) bool {
@@ -189,23 +218,61 @@ func getOrCreateOutputAnchor(sharedMemory *goPluginSharedMemory, name string) *g
 	}
 }
 
-func registerAndInit(plugin Plugin, data *goPluginSharedMemory, provider Provider) {
+func registerAndInit(plugin Plugin, data *goPluginSharedMemory, provider Provider) bool {
 	tools[data] = plugin
-	plugin.Init(provider)
+	if adapter, ok := plugin.(*pluginAdapter); ok {
+		adapter.data = data
+	}
+	return callPlugin(data, func() {
+		plugin.Init(provider)
+	})
+}
+
+func callPlugin(data *goPluginSharedMemory, callback func()) (succeeded bool) {
+	if data.hasFailed == 1 {
+		return false
+	}
+	if recoveringTools[data] {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				failPlugin(data, fmt.Sprintf("%v\n%v", recovered, string(debug.Stack())))
+				succeeded = false
+			}
+		}()
+	}
+	callback()
+	return data.hasFailed == 0
+}
+
+func failPlugin(data *goPluginSharedMemory, message string) {
+	data.hasFailed = 1
+	sendMessageToEngine(data, Error, message)
+	C.closeAllOutputAnchors((*C.struct_OutputAnchor)(unsafe.Pointer(data.outputAnchors)))
+}
+
+func callbackResult(succeeded bool) C.long {
+	if succeeded {
+		return 1
+	}
+	return 0
 }
 
 func generateIncomingConnectionInterface() unsafe.Pointer {
 	return unsafe.Pointer(C.generateIncomingConnectionInterface())
 }
 
-func callPiAddIncomingConnection(plugin *goPluginSharedMemory, name string, ii unsafe.Pointer) {
+func callPiAddIncomingConnection(plugin *goPluginSharedMemory, name string, connectionName string, ii unsafe.Pointer) {
 	namePtr := stringToUtf16Ptr(name)
-	C.callPiAddIncomingConnection((*C.struct_PluginSharedMemory)(unsafe.Pointer(plugin)), namePtr, (*C.struct_IncomingConnectionInterface)(ii))
+	connectionNamePtr := stringToUtf16Ptr(connectionName)
+	C.callPiAddIncomingConnection((*C.struct_PluginSharedMemory)(unsafe.Pointer(plugin)), namePtr, connectionNamePtr, (*C.struct_IncomingConnectionInterface)(ii))
+	freeCache(unsafe.Pointer(connectionNamePtr))
 }
 
-func callPiAddIncomingConnectionNoCache(plugin *goPluginSharedMemory, name string, ii unsafe.Pointer) {
+func callPiAddIncomingConnectionNoCache(plugin *goPluginSharedMemory, name string, connectionName string, ii unsafe.Pointer) {
 	namePtr := stringToUtf16Ptr(name)
-	C.callPiAddIncomingConnectionNoCache((*C.struct_PluginSharedMemory)(unsafe.Pointer(plugin)), namePtr, (*C.struct_IncomingConnectionInterface)(ii))
+	connectionNamePtr := stringToUtf16Ptr(connectionName)
+	C.callPiAddIncomingConnectionNoCache((*C.struct_PluginSharedMemory)(unsafe.Pointer(plugin)), namePtr, connectionNamePtr, (*C.struct_IncomingConnectionInterface)(ii))
+	freeCache(unsafe.Pointer(connectionNamePtr))
 }
 
 func callPiAddOutgoingConnection(plugin *goPluginSharedMemory, name string, ii unsafe.Pointer) {
@@ -213,18 +280,36 @@ func callPiAddOutgoingConnection(plugin *goPluginSharedMemory, name string, ii u
 	C.callPiAddOutgoingConnection((*C.struct_PluginSharedMemory)(unsafe.Pointer(plugin)), namePtr, (*C.struct_IncomingConnectionInterface)(ii))
 }
 
-func RegisterTool(plugin Plugin, toolId int, xmlProperties unsafe.Pointer, engineInterface unsafe.Pointer, pluginInterface unsafe.Pointer, optionSetters ...ToolOptionSetter) int {
+func configurePlugin(toolId int, xmlProperties unsafe.Pointer, engineInterface unsafe.Pointer, pluginInterface unsafe.Pointer, noCache bool) *goPluginSharedMemory {
+	if noCache {
+		return (*goPluginSharedMemory)(C.configurePluginNoCache(C.uint32_t(toolId), (*C.utf16char)(xmlProperties), (*C.struct_EngineInterface)(engineInterface), (*C.struct_PluginInterface)(pluginInterface)))
+	}
+	return (*goPluginSharedMemory)(C.configurePlugin(C.uint32_t(toolId), (*C.utf16char)(xmlProperties), (*C.struct_EngineInterface)(engineInterface), (*C.struct_PluginInterface)(pluginInterface)))
+}
+
+func RegisterTool(plugin interface{}, toolId int, xmlProperties unsafe.Pointer, engineInterface unsafe.Pointer, pluginInterface unsafe.Pointer, optionSetters ...ToolOptionSetter) int {
 	options := toolOptions{}
 	for _, setter := range optionSetters {
 		options = setter(options)
 	}
-	var data *goPluginSharedMemory
-	if options.noCache {
-		data = (*goPluginSharedMemory)(C.configurePluginNoCache(C.uint32_t(toolId), (*C.utf16char)(xmlProperties), (*C.struct_EngineInterface)(engineInterface), (*C.struct_PluginInterface)(pluginInterface)))
-	} else {
-		data = (*goPluginSharedMemory)(C.configurePlugin(C.uint32_t(toolId), (*C.utf16char)(xmlProperties), (*C.struct_EngineInterface)(engineInterface), (*C.struct_PluginInterface)(pluginInterface)))
+	data := configurePlugin(toolId, xmlProperties, engineInterface, pluginInterface, options.noCache)
+	for anchor, info := range options.presorts {
+		appendPresort(data, anchor, info)
+	}
+	if options.noBrowseEverywhere {
+		data.browseEverywhere = 0
+	}
+	tool, err := asPlugin(plugin)
+	if err != nil {
+		failPlugin(data, err.Error())
+		return 0
 	}
-	io := &ayxIo{sharedMemory: data}
+	ctx, cancel := context.WithCancel(context.Background())
+	decryptor := options.decryptor
+	if decryptor == nil {
+		decryptor = alteryxPasswordDecryptor{}
+	}
+	io := &ayxIo{sharedMemory: data, cancel: cancel, decryptor: decryptor}
 	environment := &ayxEnvironment{sharedMemory: data}
 	var toolProvider Provider
 	if options.noCache {
@@ -233,6 +318,7 @@ func RegisterTool(plugin Plugin, toolId int, xmlProperties unsafe.Pointer, engin
 			io:            io,
 			environment:   environment,
 			outputAnchors: make(map[string]*outputAnchorNoCache),
+			ctx:           ctx,
 		}
 	} else {
 		toolProvider = &provider{
@@ -240,19 +326,30 @@ func RegisterTool(plugin Plugin, toolId int, xmlProperties unsafe.Pointer, engin
 			io:            io,
 			environment:   environment,
 			outputAnchors: make(map[string]*outputAnchor),
+			ctx:           ctx,
 		}
 	}
-	registerAndInit(plugin, data, toolProvider)
+	registerCleanup(toolProvider, cancel)
+	recoveringTools[data] = true
+	migrateToolConfig(data, toolProvider, options.configMigrator)
+	if !registerAndInit(tool, data, toolProvider) {
+		return 0
+	}
 	return 1
 }
 
-func RegisterToolTest(plugin Plugin, toolId int, xmlProperties string, optionSetters ...OptionSetter) *FileTestRunner {
+func RegisterToolTest(plugin interface{}, toolId int, xmlProperties string, optionSetters ...OptionSetter) *FileTestRunner {
+	tool, err := asPlugin(plugin)
+	if err != nil {
+		panic(err.Error())
+	}
 	options := testOptions{
 		updateOnly:  false,
 		updateMode:  "",
 		workflowDir: "",
 		locale:      "en",
 		noCache:     false,
+		recordLimit: -1,
 	}
 	for _, optionSetter := range optionSetters {
 		options = optionSetter(options)
@@ -260,20 +357,22 @@ func RegisterToolTest(plugin Plugin, toolId int, xmlProperties string, optionSet
 	xmlRunes := []rune(xmlProperties)
 	xmlUtf16 := append(utf16.Encode(xmlRunes), 0)
 	xmlPtr := unsafe.Pointer(&xmlUtf16[0])
-	pluginInterface := unsafe.Pointer(C.generatePluginInterface())
-	var data *goPluginSharedMemory
-	if options.noCache {
-		data = (*goPluginSharedMemory)(C.configurePluginNoCache(C.uint32_t(toolId), (*C.utf16char)(xmlPtr), nil, (*C.struct_PluginInterface)(pluginInterface)))
-	} else {
-		data = (*goPluginSharedMemory)(C.configurePlugin(C.uint32_t(toolId), (*C.utf16char)(xmlPtr), nil, (*C.struct_PluginInterface)(pluginInterface)))
+	pluginInterface := generatePluginInterface()
+	messages := &testMessageLog{}
+	engine := newTestEngine(options.noCache, messages)
+	data := configurePlugin(toolId, xmlPtr, engine.handle, pluginInterface, options.noCache)
+	if options.noBrowseEverywhere {
+		data.browseEverywhere = 0
 	}
-	io := &testIo{}
+	ctx, cancel := context.WithCancel(context.Background())
+	io := &testIo{log: messages, cancel: cancel, cancelAfter: options.cancelAfter, decryptor: options.decryptor, tempFileDir: options.tempFileDir}
 	environment := &testEnvironment{
 		sharedMemory: data,
 		updateOnly:   options.updateOnly,
 		updateMode:   options.updateMode,
 		workflowDir:  options.workflowDir,
 		locale:       options.locale,
+		log:          messages,
 	}
 	var toolProvider Provider
 	if options.noCache {
@@ -282,6 +381,7 @@ func RegisterToolTest(plugin Plugin, toolId int, xmlProperties string, optionSet
 			io:            io,
 			environment:   environment,
 			outputAnchors: make(map[string]*outputAnchorNoCache),
+			ctx:           ctx,
 		}
 	} else {
 		toolProvider = &provider{
@@ -289,14 +389,26 @@ func RegisterToolTest(plugin Plugin, toolId int, xmlProperties string, optionSet
 			io:            io,
 			environment:   environment,
 			outputAnchors: make(map[string]*outputAnchor),
+			ctx:           ctx,
 		}
 	}
-	registerAndInit(plugin, data, toolProvider)
+	registerCleanup(toolProvider, cancel)
+	if options.recoverPanics {
+		recoveringTools[data] = true
+	}
+	migrateToolConfig(data, toolProvider, options.configMigrator)
+	registerAndInit(tool, data, toolProvider)
+	adapter, _ := tool.(*pluginAdapter)
 	return &FileTestRunner{
-		noCache: options.noCache,
-		io:      io,
-		plugin:  data,
-		inputs:  make(map[string]*FilePusher),
+		noCache:     options.noCache,
+		io:          io,
+		plugin:      data,
+		inputs:      []*FilePusher{},
+		presorts:    options.presorts,
+		engine:      engine,
+		messages:    messages,
+		recordLimit: options.recordLimit,
+		adapter:     adapter,
 	}
 }
 
@@ -317,7 +429,7 @@ func registerTestHarness(plugin Plugin, noCache bool) *goPluginSharedMemory {
 		break
 	}
 
-	pluginInterface := unsafe.Pointer(C.generatePluginInterface())
+	pluginInterface := generatePluginInterface()
 	config := stringToUtf16Ptr("<Configuration></Configuration>")
 	var data *goPluginSharedMemory
 	if noCache {
@@ -336,6 +448,7 @@ func registerTestHarness(plugin Plugin, noCache bool) *goPluginSharedMemory {
 			io:            io,
 			environment:   environment,
 			outputAnchors: make(map[string]*outputAnchorNoCache),
+			ctx:           context.Background(),
 		}
 	} else {
 		toolProvider = &provider{
@@ -343,88 +456,134 @@ func registerTestHarness(plugin Plugin, noCache bool) *goPluginSharedMemory {
 			io:            io,
 			environment:   environment,
 			outputAnchors: make(map[string]*outputAnchor),
+			ctx:           context.Background(),
 		}
 	}
 	registerAndInit(plugin, data, toolProvider)
 	return data
 }
 
+func appendPresort(sharedMemory *goPluginSharedMemory, anchor string, info PresortInfo) {
+	anchorPtr := stringToUtf16Ptr(anchor)
+	sortInfoPtr := stringToUtf16Ptr(info.toXml())
+	C.appendPresort((*C.struct_PluginSharedMemory)(unsafe.Pointer(sharedMemory)), anchorPtr, sortInfoPtr)
+}
+
 func openOutgoingAnchor(anchor *goOutputAnchorData, config string) {
 	configPtr := stringToUtf16Ptr(config)
 	C.openOutgoingAnchor((*C.struct_OutputAnchor)(unsafe.Pointer(anchor)), configPtr)
 }
 
 //export goOnInputConnectionOpened
-func goOnInputConnectionOpened(handle unsafe.Pointer) {
+func goOnInputConnectionOpened(handle unsafe.Pointer) C.long {
 	var data = (*goInputConnectionData)(handle)
 	plugin := tools[data.plugin]
 	inputConnection := &ImpInputConnection{
 		data: data,
 	}
-	var hasVarFields byte = 0
-	var fixedSize uint32 = 0
-	fields := inputConnection.Metadata().fields
-	for _, field := range fields {
-		switch field.Type {
-		case `Bool`:
-			fixedSize += 1
-		case `Byte`:
-			fixedSize += 2
-		case `Int16`:
-			fixedSize += 3
-		case `Int32`, `Float`:
-			fixedSize += 5
-		case `Int64`, `Double`:
-			fixedSize += 9
-		case `FixedDecimal`:
-			fixedSize += uint32(field.Size) + 1
-		case `Date`:
-			fixedSize += 11
-		case `DateTime`:
-			fixedSize += 20
-		case `Time`:
-			fixedSize += 9
-		case `String`:
-			fixedSize += uint32(field.Size) + 1
-		case `WString`:
-			fixedSize += uint32(field.Size*2) + 1
-		case `V_String`, `V_WString`, `Blob`, `SpatialObj`:
-			fixedSize += 4
-			hasVarFields = 1
-		}
+	metadata := inputConnection.Metadata()
+	data.fixedSize = uint32(metadata.fixedSize)
+	if metadata.hasVarFields {
+		data.hasVarFields = 1
 	}
-	data.fixedSize = fixedSize
-	data.hasVarFields = hasVarFields
-	plugin.OnInputConnectionOpened(inputConnection)
+	return callbackResult(callPlugin(data.plugin, func() {
+		plugin.OnInputConnectionOpened(inputConnection)
+	}))
 }
 
 //export goOnRecordPacket
-func goOnRecordPacket(handle unsafe.Pointer) {
+func goOnRecordPacket(handle unsafe.Pointer) C.long {
 	data := (*goInputConnectionData)(handle)
 	connection := &ImpInputConnection{data: data}
 	implementation := tools[data.plugin]
-	implementation.OnRecordPacket(connection)
+	return callbackResult(callPlugin(data.plugin, func() {
+		implementation.OnRecordPacket(connection)
+	}))
 }
 
 //export goOnRecordPacketNoCache
-func goOnRecordPacketNoCache(handle unsafe.Pointer) {
+func goOnRecordPacketNoCache(handle unsafe.Pointer) C.long {
 	data := (*goInputConnectionData)(handle)
 	connection := &ImpInputConnectionNoCache{data: data}
 	implementation := tools[data.plugin]
-	implementation.OnRecordPacket(connection)
+	return callbackResult(callPlugin(data.plugin, func() {
+		implementation.OnRecordPacket(connection)
+	}))
+}
+
+//export goOnInputConnectionClosed
+func goOnInputConnectionClosed(handle unsafe.Pointer) {
+	data := (*goInputConnectionData)(handle)
+	if closer, ok := tools[data.plugin].(ConnectionCloser); ok {
+		callPlugin(data.plugin, func() {
+			closer.OnInputConnectionClosed(&ImpInputConnection{data: data})
+		})
+	}
+}
+
+//export goOnInputConnectionClosedNoCache
+func goOnInputConnectionClosedNoCache(handle unsafe.Pointer) {
+	data := (*goInputConnectionData)(handle)
+	if closer, ok := tools[data.plugin].(ConnectionCloser); ok {
+		callPlugin(data.plugin, func() {
+			closer.OnInputConnectionClosed(&ImpInputConnectionNoCache{data: data})
+		})
+	}
 }
 
 //export goOnComplete
-func goOnComplete(handle unsafe.Pointer) {
+func goOnComplete(handle unsafe.Pointer) C.long {
 	data := (*goPluginSharedMemory)(handle)
 	implementation := tools[data]
-	implementation.OnComplete()
-	for anchor := data.outputAnchors; anchor != nil; anchor = anchor.nextAnchor {
+	succeeded := callPlugin(data, implementation.OnComplete)
+	for anchor := data.outputAnchors; succeeded && anchor != nil; anchor = anchor.nextAnchor {
 		if anchor.recordCachePosition > 0 {
 			callWriteRecords(unsafe.Pointer(anchor))
 		}
 	}
+	releaseTool(data)
+	return callbackResult(succeeded)
+}
+
+//export goOnDownstreamClosed
+func goOnDownstreamClosed(handle unsafe.Pointer) {
+	data := (*goOutputAnchorData)(handle)
+	switch anchor := toolOutputAnchors[data].(type) {
+	case *outputAnchor:
+		notifyIfDownstreamClosed(anchor, data, &anchor.closedNotified)
+	case *outputAnchorNoCache:
+		notifyIfDownstreamClosed(anchor, data, &anchor.closedNotified)
+	}
+}
+
+//export goOnClose
+func goOnClose(handle unsafe.Pointer) {
+	releaseTool((*goPluginSharedMemory)(handle))
+}
+
+func releaseTool(data *goPluginSharedMemory) {
+	for _, cleanup := range toolCleanups[data] {
+		cleanup()
+	}
 	delete(tools, data)
+	delete(recoveringTools, data)
+	delete(toolCleanups, data)
+	for anchor := data.outputAnchors; anchor != nil; anchor = anchor.nextAnchor {
+		delete(toolOutputAnchors, anchor)
+	}
+}
+
+func registerCleanup(toolProvider Provider, cleanup func()) {
+	var data *goPluginSharedMemory
+	switch typed := toolProvider.(type) {
+	case *provider:
+		data = typed.sharedMemory
+	case *providerNoCache:
+		data = typed.sharedMemory
+	default:
+		return
+	}
+	toolCleanups[data] = append(toolCleanups[data], cleanup)
 }
 
 func callWriteRecord(handle unsafe.Pointer) {
@@ -449,3 +608,29 @@ func allocateCache(size uint32) unsafe.Pointer {
 func freeCache(cache unsafe.Pointer) {
 	C.free(cache)
 }
+
+func generateTestEngine() unsafe.Pointer {
+	return unsafe.Pointer(C.generateTestEngine())
+}
+
+func generatePluginInterface() unsafe.Pointer {
+	return unsafe.Pointer(C.generatePluginInterface())
+}
+
+//export goTestEngineOutputMessage
+func goTestEngineOutputMessage(handle unsafe.Pointer, toolId C.int, status C.int, message *C.utf16char) C.long {
+	text := utf16PtrToString(unsafe.Pointer(message), utf16PtrLen(unsafe.Pointer(message)))
+	testEngines[handle].log.addMessage(MessageStatus(status), text)
+	return 1
+}
+
+//export goTestEngineBrowseEverywhereReserveAnchor
+func goTestEngineBrowseEverywhereReserveAnchor(handle unsafe.Pointer, toolId C.int) C.unsigned {
+	return C.unsigned(testEngines[handle].reserveBrowseEverywhere())
+}
+
+//export goTestEngineBrowseEverywhereGetII
+func goTestEngineBrowseEverywhereGetII(handle unsafe.Pointer, reservationId C.unsigned, toolId C.int, outputName *C.utf16char) unsafe.Pointer {
+	name := utf16PtrToString(unsafe.Pointer(outputName), utf16PtrLen(unsafe.Pointer(outputName)))
+	return testEngines[handle].getBrowseEverywhereIi(name)
+}
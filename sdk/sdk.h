@@ -18,6 +18,9 @@ struct InputConnection {
     char*                      recordCache;
     uint32_t                   recordCachePosition;
     uint32_t                   recordCacheSize;
+    utf16char*                 name;
+    int64_t                    acceptLimit;
+    int64_t                    acceptedRecords;
 };
 
 struct InputAnchor {
@@ -30,6 +33,8 @@ struct OutputConn {
     char                                isOpen;
     struct IncomingConnectionInterface* ii;
     struct OutputConn*                  nextConnection;
+    char                                isBrowseEverywhere;
+    char                                isClosedDownstream;
 };
 
 struct OutputAnchor {
@@ -49,6 +54,12 @@ struct OutputAnchor {
     uint64_t                   totalDataSize;
 };
 
+struct Presort {
+    utf16char*      anchor;
+    utf16char*      sortInfo;
+    struct Presort* nextPresort;
+};
+
 struct PluginSharedMemory {
     uint32_t                toolId;
     utf16char*              toolConfig;
@@ -59,14 +70,19 @@ struct PluginSharedMemory {
     uint32_t                totalInputConnections;
     uint32_t                closedInputConnections;
     struct InputAnchor*     inputAnchors;
+    struct Presort*         presorts;
+    char                    browseEverywhere;
+    int64_t                 recordLimit;
+    char                    hasFailed;
 };
 
 struct PluginInterface* generatePluginInterface();
 struct IncomingConnectionInterface* generateIncomingConnectionInterface();
-void callPiAddIncomingConnection(struct PluginSharedMemory *handle, utf16char * name, struct IncomingConnectionInterface *ii);
-void callPiAddIncomingConnectionNoCache(struct PluginSharedMemory *handle, utf16char * name, struct IncomingConnectionInterface *ii);
+void callPiAddIncomingConnection(struct PluginSharedMemory *handle, utf16char * name, utf16char * connectionName, struct IncomingConnectionInterface *ii);
+void callPiAddIncomingConnectionNoCache(struct PluginSharedMemory *handle, utf16char * name, utf16char * connectionName, struct IncomingConnectionInterface *ii);
 void callPiAddOutgoingConnection(struct PluginSharedMemory *handle, utf16char * name, struct IncomingConnectionInterface *ii);
-void simulateInputLifecycle(struct PluginInterface *pluginInterface);
+void simulateInputLifecycle(struct PluginInterface *pluginInterface, int64_t recordLimit);
+void closePlugin(struct PluginInterface *pluginInterface);
 void sendMessage(struct EngineInterface * engine, int nToolID, int nStatus, utf16char *pMessage);
 long outputToolProgress(struct EngineInterface * engine, int nToolID, double progress);
 void sendProgressToAnchor(struct OutputAnchor *anchor, double progress);
@@ -75,8 +91,11 @@ void* createTempFile(struct EngineInterface * engine, utf16char *pExt);
 void* configurePlugin(uint32_t nToolID, utf16char * pXmlProperties, struct EngineInterface *pEngineInterface, struct PluginInterface *r_pluginInterface);
 void* configurePluginNoCache(uint32_t nToolID, utf16char * pXmlProperties, struct EngineInterface *pEngineInterface, struct PluginInterface *r_pluginInterface);
 struct OutputAnchor* appendOutgoingAnchor(struct PluginSharedMemory* plugin, utf16char * name);
+void appendPresort(struct PluginSharedMemory* plugin, utf16char * anchor, utf16char * sortInfo);
 void openOutgoingAnchor(struct OutputAnchor *anchor, utf16char * config);
 void closeOutputAnchor(struct OutputAnchor *anchor);
+void closeAllOutputAnchors(struct OutputAnchor *anchor);
+void freeAllAnchors(struct PluginSharedMemory *plugin);
 void PI_Close(void * handle, bool bHasErrors);
 long PI_PushAllRecords(void * handle, int64_t nRecordLimit);
 long PI_AddIncomingConnection(void * handle,
@@ -97,10 +116,18 @@ void II_UpdateProgress(void * handle, double dPercent);
 void II_Close(void * handle);
 void II_CloseNoCache(void * handle);
 void II_Free(void * handle);
-void goOnInputConnectionOpened(void * handle);
-void goOnRecordPacket(void * handle);
-void goOnRecordPacketNoCache(void * handle);
-void goOnComplete(void * handle);
+long goOnInputConnectionOpened(void * handle);
+long goOnRecordPacket(void * handle);
+long goOnRecordPacketNoCache(void * handle);
+void goOnInputConnectionClosed(void * handle);
+void goOnInputConnectionClosedNoCache(void * handle);
+long goOnComplete(void * handle);
+void goOnClose(void * handle);
+void goOnDownstreamClosed(void * handle);
+long goTestEngineOutputMessage(void * handle, int nToolID, int nStatus, utf16char *pMessage);
+unsigned goTestEngineBrowseEverywhereReserveAnchor(void * handle, int nToolId);
+void* goTestEngineBrowseEverywhereGetII(void * handle, unsigned nReservationId, int nToolId, utf16char * strOutputName);
+struct EngineInterface* generateTestEngine();
 void callWriteRecord(struct OutputAnchor *anchor);
 void callWriteRecords(struct OutputAnchor *anchor);
 void* allocateCache(int size);
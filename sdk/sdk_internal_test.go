@@ -1,6 +1,12 @@
 package sdk
 
-import "testing"
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"unsafe"
+)
 
 type InternalTest struct{}
 
@@ -30,3 +36,133 @@ func TestPluginsAreRemovedAfterOnComplete(t *testing.T) {
 	}
 
 }
+
+func TestPresortInfoToXml(t *testing.T) {
+	info := PresortInfo{
+		SortFields: []SortField{
+			{Field: `Field1`, Order: Ascending},
+			{Field: `A&B`, Order: Descending},
+		},
+		SelectFields: []string{`Field1`, `A&B`},
+	}
+	expected := `<SortInfo><Field field="Field1" order="Asc"></Field><Field field="A&amp;B" order="Desc"></Field></SortInfo><FieldFilterList><Field field="Field1"></Field><Field field="A&amp;B"></Field></FieldFilterList>`
+	if actual := info.toXml(); actual != expected {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, actual)
+	}
+
+	info = PresortInfo{SortFields: []SortField{{Field: `Field1`}}}
+	expected = `<SortInfo><Field field="Field1" order="Asc"></Field></SortInfo>`
+	if actual := info.toXml(); actual != expected {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, actual)
+	}
+}
+
+func TestLzf(t *testing.T) {
+	output := make([]byte, 100)
+	length, err := lzfDecompress([]byte{2, 'a', 'b', 'c', 0xE0, 0, 2}, output)
+	if err != nil || string(output[:length]) != `abcabcabcabc` {
+		t.Fatalf(`expected 'abcabcabcabc' but got '%v' and %v`, string(output[:length]), err)
+	}
+
+	input := append(bytes.Repeat([]byte(`the quick brown fox `), 500), []byte(`jumps over the lazy dog`)...)
+	compressed := lzfCompress(input)
+	if len(compressed) >= len(input) {
+		t.Fatalf(`expected compressed data to be smaller than %v bytes but got %v`, len(input), len(compressed))
+	}
+	output = make([]byte, len(input))
+	length, err = lzfDecompress(compressed, output)
+	if err != nil || !bytes.Equal(input, output[:length]) {
+		t.Fatalf(`expected decompressed data to match the input but got %v bytes and %v`, length, err)
+	}
+
+	_, err = lzfDecompress([]byte{0xE0, 0, 2}, output)
+	if err == nil {
+		t.Fatalf(`expected an error for a corrupt block but got none`)
+	}
+}
+
+func TestRegisterEntryReportsUnregisteredName(t *testing.T) {
+	log := &testMessageLog{}
+	engine := newTestEngine(false, log)
+	xmlProperties := stringToUtf16Ptr(``)
+	result := RegisterEntry(`Unregistered`, 1, unsafe.Pointer(xmlProperties), engine.handle, generatePluginInterface())
+	if result != 0 {
+		t.Fatalf(`expected 0 but got %v`, result)
+	}
+	expected := `no tool is registered as 'Unregistered'; the name passed to sdk.Register must be the name of the tool's directory`
+	if len(log.messages) != 1 || log.messages[0].Status != Error || log.messages[0].Message != expected {
+		t.Fatalf(`expected an error message '%v' but got %v`, expected, log.messages)
+	}
+}
+
+func TestCloseReleasesToolsThatNeverCompleted(t *testing.T) {
+	runner := RegisterToolTest(&InternalTest{}, 1, ``)
+	cleanedUp := false
+	registerCleanup(&provider{sharedMemory: runner.plugin}, func() { cleanedUp = true })
+
+	closePlugin(runner.plugin.ayxInterface)
+
+	if !cleanedUp {
+		t.Fatalf(`expected the cleanup to run but it did not`)
+	}
+	if _, ok := tools[runner.plugin]; ok {
+		t.Fatalf(`expected the tool to be released but it was not`)
+	}
+}
+
+func TestCompareStringsIgnoreCase(t *testing.T) {
+	tests := []struct {
+		first    string
+		second   string
+		expected int
+	}{
+		{`Apple`, `apple`, 0},
+		{`apple`, `Banana`, -1},
+		{`ÉCOLE`, `école`, 0},
+		{`abc`, `ABCD`, -1},
+		{`b`, `A`, 1},
+	}
+	for _, test := range tests {
+		if compared := compareStringsIgnoreCase(test.first, test.second); compared != test.expected {
+			t.Fatalf(`expected comparing '%v' to '%v' to give %v but got %v`, test.first, test.second, test.expected, compared)
+		}
+	}
+	allocations := testing.AllocsPerRun(100, func() {
+		compareStringsIgnoreCase(`Hello, World`, `hello, world`)
+	})
+	if allocations != 0 {
+		t.Fatalf(`expected no allocations but got %v`, allocations)
+	}
+}
+
+func TestCompareSortValues(t *testing.T) {
+	large, _ := ParseDecimal(`1234567890123456.78`)
+	larger, _ := ParseDecimal(`1234567890123456.79`)
+	if compared := compareSortValues(large, larger); compared != -1 {
+		t.Fatalf(`expected -1 but got %v`, compared)
+	}
+	if compared := compareSortValues(math.NaN(), 1.0); compared != 1 {
+		t.Fatalf(`expected NaN to sort after numbers but got %v`, compared)
+	}
+	if compared := compareSortValues(nil, math.NaN()); compared != -1 {
+		t.Fatalf(`expected null to sort before NaN but got %v`, compared)
+	}
+}
+
+func TestGetCurrentDecimalPanicsOnInvalidData(t *testing.T) {
+	info, _ := NewOutgoingRecordInfo([]NewOutgoingField{NewFixedDecimalField(`Amount`, `source`, 19, 2)})
+	field := info.DecimalFields[`Amount`].(*outgoingField)
+	_ = field.SetDecimal(NewDecimal(1, 2))
+	copy(field.CurrentValue, `abc`)
+
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			t.Fatalf(`expected a panic but got none`)
+		}
+		if message, ok := recovered.(string); !ok || !strings.HasPrefix(message, `error reading field 'Amount'`) {
+			t.Fatalf(`expected an error reading field 'Amount' but got %v`, recovered)
+		}
+	}()
+	field.GetCurrentDecimal()
+}
@@ -2,6 +2,7 @@ package sdk_test
 
 import (
 	"github.com/tlarsendataguy/goalteryx/sdk"
+	"reflect"
 	"testing"
 )
 
@@ -62,3 +63,48 @@ func TestNoCache(t *testing.T) {
 		t.Fatalf("expected 'QRSTU\r\nVWXYZ' but got '%v", field12[1])
 	}
 }
+
+func TestInputAnchorsNoCache(t *testing.T) {
+	plugin := &inputAnchorTester{}
+	runner := sdk.RegisterToolTest(plugin, 1, ``, sdk.NoCache(true))
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if plugin.inputConnections != 2 {
+		t.Fatalf(`expected 2 connections on Input but got %v`, plugin.inputConnections)
+	}
+	if !plugin.allClosed {
+		t.Fatalf(`expected all anchors to be closed during OnComplete`)
+	}
+	if plugin.fieldsAfterClose != 34 {
+		t.Fatalf(`expected 34 fields across closed connections but got %v`, plugin.fieldsAfterClose)
+	}
+}
+
+func TestConnectionCloserNoCache(t *testing.T) {
+	plugin := &connectionCloseTester{}
+	runner := sdk.RegisterToolTest(plugin, 1, ``, sdk.NoCache(true))
+	runner.ConnectInput(`Left`, `sdk_test_passthrough_simulation.txt`)
+	runner.ConnectInput(`Right`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if expected := []int{4, 4}; !reflect.DeepEqual(expected, plugin.recordsAtClose) {
+		t.Fatalf(`expected %v but got %v`, expected, plugin.recordsAtClose)
+	}
+	if plugin.closedAfterComplete {
+		t.Fatalf(`expected connections to close before OnComplete`)
+	}
+}
+
+func TestBrowseEverywhereNoCache(t *testing.T) {
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.NoCache(true))
+	browse := runner.CaptureBrowseEverywhere(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if recordCount := len(browse.Data[`Field1`]); recordCount != 4 {
+		t.Fatalf(`expected 4 records but got %v`, recordCount)
+	}
+}
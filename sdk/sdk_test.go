@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/tlarsendataguy/goalteryx/sdk"
+	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
@@ -369,7 +370,7 @@ func TestOutputRecordsToTestRunner(t *testing.T) {
 		if collector.Data[`Field8`][i] != float64(i) {
 			t.Fatalf(`expected [0 1 2 3 4 5 6 7 8 9] but got %v`, collector.Data[`Field8`])
 		}
-		if collector.Data[`Field9`][i] != float64(i) {
+		if value := collector.Data[`Field9`][i].(sdk.Decimal); value.Cmp(sdk.NewDecimal(int64(i), 0)) != 0 {
 			t.Fatalf(`expected [0 1 2 3 4 5 6 7 8 9] but got %v`, collector.Data[`Field9`])
 		}
 		if collector.Data[`Field10`][i] != strconv.Itoa(i) {
@@ -494,7 +495,7 @@ func TestPassthroughSimulation(t *testing.T) {
 	if expectedValues := []interface{}{1.23, -1.23, nil, 41.22}; !reflect.DeepEqual(expectedValues, collector.Data[`Field7`]) {
 		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Field7`])
 	}
-	if expectedValues := []interface{}{234.56, -234.56, nil, 98.2}; !reflect.DeepEqual(expectedValues, collector.Data[`Field8`]) {
+	if expectedValues := []interface{}{`234.56`, `-234.56`, nil, `98.20`}; !reflect.DeepEqual(expectedValues, decimalStrings(collector.Data[`Field8`])) {
 		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Field8`])
 	}
 	if expectedValues := []interface{}{`ABC`, `DE|"FG`, nil, ``}; !reflect.DeepEqual(expectedValues, collector.Data[`Field9`]) {
@@ -684,6 +685,85 @@ func TestStatusMultipleInputs(t *testing.T) {
 	}
 }
 
+type inputAnchorTester struct {
+	input             sdk.InputAnchor
+	right             sdk.InputAnchor
+	closedOnOpen      []bool
+	connectionNames   []string
+	anchorNames       []string
+	inputConnections  int
+	rightConnections  int
+	allClosed         bool
+	fieldsAfterClose  int
+	missingConnection int
+}
+
+func (i *inputAnchorTester) Init(provider sdk.Provider) {
+	i.input = provider.GetInputAnchor(`Input`)
+	i.right = provider.GetInputAnchor(`Right`)
+}
+
+func (i *inputAnchorTester) OnInputConnectionOpened(connection sdk.InputConnection) {
+	i.connectionNames = append(i.connectionNames, connection.ConnectionName())
+	i.anchorNames = append(i.anchorNames, connection.Anchor().Name())
+	i.closedOnOpen = append(i.closedOnOpen, i.input.AllClosed())
+}
+
+func (i *inputAnchorTester) OnRecordPacket(_ sdk.InputConnection) {}
+
+func (i *inputAnchorTester) OnComplete() {
+	i.inputConnections = len(i.input.Connections())
+	i.rightConnections = len(i.right.Connections())
+	i.allClosed = i.input.AllClosed() && i.right.AllClosed()
+	for _, connection := range i.input.Connections() {
+		i.fieldsAfterClose += connection.Metadata().NumFields()
+	}
+}
+
+func TestInputAnchors(t *testing.T) {
+	plugin := &inputAnchorTester{}
+	runner := sdk.RegisterToolTest(plugin, 1, ``)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.ConnectInput(`Right`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if plugin.inputConnections != 2 {
+		t.Fatalf(`expected 2 connections on Input but got %v`, plugin.inputConnections)
+	}
+	if plugin.rightConnections != 1 {
+		t.Fatalf(`expected 1 connection on Right but got %v`, plugin.rightConnections)
+	}
+	if expected := []string{`#1`, `#2`, `#1`}; !reflect.DeepEqual(expected, plugin.connectionNames) {
+		t.Fatalf(`expected %v but got %v`, expected, plugin.connectionNames)
+	}
+	if expected := []string{`Input`, `Input`, `Right`}; !reflect.DeepEqual(expected, plugin.anchorNames) {
+		t.Fatalf(`expected %v but got %v`, expected, plugin.anchorNames)
+	}
+	if expected := []bool{false, false, true}; !reflect.DeepEqual(expected, plugin.closedOnOpen) {
+		t.Fatalf(`expected %v but got %v`, expected, plugin.closedOnOpen)
+	}
+	if !plugin.allClosed {
+		t.Fatalf(`expected all anchors to be closed during OnComplete`)
+	}
+	if plugin.fieldsAfterClose != 34 {
+		t.Fatalf(`expected 34 fields across closed connections but got %v`, plugin.fieldsAfterClose)
+	}
+}
+
+func TestInputAnchorWithoutConnections(t *testing.T) {
+	plugin := &inputAnchorTester{}
+	runner := sdk.RegisterToolTest(plugin, 1, ``)
+	runner.SimulateLifecycle()
+
+	if plugin.inputConnections != 0 {
+		t.Fatalf(`expected 0 connections but got %v`, plugin.inputConnections)
+	}
+	if !plugin.allClosed {
+		t.Fatalf(`expected an anchor without connections to be closed`)
+	}
+}
+
 type outputAnchorCloseTester struct {
 	output1 sdk.OutputAnchor
 	output2 sdk.OutputAnchor
@@ -762,6 +842,7 @@ func TestCreateTempFile(t *testing.T) {
 		t.Fatalf(`expected '.yxdb' but got '%v'`, ext)
 	}
 	t.Logf(plugin.filePath)
+	_ = os.Remove(plugin.filePath)
 }
 
 type byteTester struct {
@@ -805,3 +886,640 @@ func TestWriteBytes(t *testing.T) {
 		t.Fatalf(`expected 250 but got %v`, bytesField[0])
 	}
 }
+
+type connectionCloseTester struct {
+	records             map[string]int
+	closed              []string
+	recordsAtClose      []int
+	statusAtClose       []sdk.Status
+	completed           bool
+	closedAfterComplete bool
+}
+
+func (c *connectionCloseTester) Init(_ sdk.Provider) {
+	c.records = make(map[string]int)
+}
+
+func (c *connectionCloseTester) OnInputConnectionOpened(_ sdk.InputConnection) {}
+
+func (c *connectionCloseTester) OnRecordPacket(connection sdk.InputConnection) {
+	packet := connection.Read()
+	for packet.Next() {
+		c.records[connection.Name()]++
+	}
+}
+
+func (c *connectionCloseTester) OnInputConnectionClosed(connection sdk.InputConnection) {
+	c.closed = append(c.closed, connection.Name())
+	c.recordsAtClose = append(c.recordsAtClose, c.records[connection.Name()])
+	c.statusAtClose = append(c.statusAtClose, connection.Status())
+	c.closedAfterComplete = c.closedAfterComplete || c.completed
+}
+
+func (c *connectionCloseTester) OnComplete() {
+	c.completed = true
+}
+
+func TestConnectionCloser(t *testing.T) {
+	plugin := &connectionCloseTester{}
+	runner := sdk.RegisterToolTest(plugin, 1, ``)
+	runner.ConnectInput(`Left`, `sdk_test_passthrough_simulation.txt`)
+	runner.ConnectInput(`Right`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if expected := []string{`Left`, `Right`}; !reflect.DeepEqual(expected, plugin.closed) {
+		t.Fatalf(`expected %v but got %v`, expected, plugin.closed)
+	}
+	if expected := []int{4, 4}; !reflect.DeepEqual(expected, plugin.recordsAtClose) {
+		t.Fatalf(`expected %v but got %v`, expected, plugin.recordsAtClose)
+	}
+	if expected := []sdk.Status{sdk.Closed, sdk.Closed}; !reflect.DeepEqual(expected, plugin.statusAtClose) {
+		t.Fatalf(`expected %v but got %v`, expected, plugin.statusAtClose)
+	}
+	if plugin.closedAfterComplete {
+		t.Fatalf(`expected connections to close before OnComplete`)
+	}
+	if !plugin.completed {
+		t.Fatalf(`expected OnComplete to be called`)
+	}
+}
+
+func TestPresortSimulation(t *testing.T) {
+	implementation := &PassThroughTool{}
+	presort := sdk.PresortInfo{
+		SortFields: []sdk.SortField{
+			{Field: `Field2`, Order: sdk.Descending},
+			{Field: `Field3`, Order: sdk.Ascending},
+		},
+		SelectFields: []string{`Field3`, `Field2`},
+	}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.Presort(`Input`, presort))
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if len(collector.Data) != 2 {
+		t.Fatalf(`expected 2 fields but got %v`, len(collector.Data))
+	}
+	if expectedValues := []interface{}{42, 2, 2, nil}; !reflect.DeepEqual(expectedValues, collector.Data[`Field2`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Field2`])
+	}
+	if expectedValues := []interface{}{-110, -100, 100, nil}; !reflect.DeepEqual(expectedValues, collector.Data[`Field3`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Field3`])
+	}
+}
+
+type browseEverywhereTester struct {
+	PassThroughTool
+	connectionsBeforeOpen int
+	connectionsAfterOpen  int
+}
+
+func (b *browseEverywhereTester) OnInputConnectionOpened(connection sdk.InputConnection) {
+	b.connectionsBeforeOpen = b.output.NumConnections()
+	b.PassThroughTool.OnInputConnectionOpened(connection)
+	b.connectionsAfterOpen = b.output.NumConnections()
+}
+
+func TestBrowseEverywhere(t *testing.T) {
+	implementation := &browseEverywhereTester{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	browse := runner.CaptureBrowseEverywhere(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if implementation.connectionsBeforeOpen != 1 || implementation.connectionsAfterOpen != 1 {
+		t.Fatalf(`expected 1 connection before and after open but got %v and %v`, implementation.connectionsBeforeOpen, implementation.connectionsAfterOpen)
+	}
+	if browse.Name != `Output` {
+		t.Fatalf(`expected a browse everywhere connection named Output but got '%v'`, browse.Name)
+	}
+	if !reflect.DeepEqual(collector.Data, browse.Data) {
+		t.Fatalf(`expected browse everywhere data %v to match output data %v`, browse.Data, collector.Data)
+	}
+	if recordCount := len(browse.Data[`Field1`]); recordCount != 4 {
+		t.Fatalf(`expected 4 records but got %v`, recordCount)
+	}
+}
+
+func TestNoBrowseEverywhere(t *testing.T) {
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.NoBrowseEverywhere(true))
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	browse := runner.CaptureBrowseEverywhere(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if recordCount := len(collector.Data[`Field1`]); recordCount != 4 {
+		t.Fatalf(`expected 4 records but got %v`, recordCount)
+	}
+	if browse.Data != nil {
+		t.Fatalf(`expected no browse everywhere data but got %v`, browse.Data)
+	}
+}
+
+type recordLimitTester struct {
+	TestInputTool
+	limit        int
+	limitReached []string
+}
+
+func (r *recordLimitTester) OnComplete() {
+	r.limit = r.Provider.Environment().RecordLimit()
+	r.TestInputTool.OnComplete()
+}
+
+func (r *recordLimitTester) OnRecordLimitReached(anchor sdk.OutputAnchor) {
+	r.limitReached = append(r.limitReached, anchor.Name())
+}
+
+func TestRecordLimit(t *testing.T) {
+	implementation := &recordLimitTester{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.RecordLimit(3))
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+
+	if implementation.limit != 3 {
+		t.Fatalf(`expected a record limit of 3 but got %v`, implementation.limit)
+	}
+	if expectedValues := []interface{}{0, 1, 2}; !reflect.DeepEqual(expectedValues, collector.Data[`Field3`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Field3`])
+	}
+	if expected := []string{`Output`}; !reflect.DeepEqual(expected, implementation.limitReached) {
+		t.Fatalf(`expected %v but got %v`, expected, implementation.limitReached)
+	}
+}
+
+func TestRecordLimitZero(t *testing.T) {
+	implementation := &recordLimitTester{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.RecordLimit(0), sdk.NoCache(true))
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+
+	if len(collector.Config.Fields()) != 17 {
+		t.Fatalf(`expected 17 fields but got %v`, len(collector.Config.Fields()))
+	}
+	if len(collector.Data[`Field3`]) != 0 {
+		t.Fatalf(`expected no records but got %v`, collector.Data[`Field3`])
+	}
+}
+
+func TestNoRecordLimitByDefault(t *testing.T) {
+	implementation := &recordLimitTester{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+
+	if implementation.limit != -1 {
+		t.Fatalf(`expected a record limit of -1 but got %v`, implementation.limit)
+	}
+	if len(collector.Data[`Field3`]) != 10 {
+		t.Fatalf(`expected 10 records but got %v`, len(collector.Data[`Field3`]))
+	}
+	if len(implementation.limitReached) != 0 {
+		t.Fatalf(`expected no notifications but got %v`, implementation.limitReached)
+	}
+}
+
+type cancellationTester struct {
+	provider   sdk.Provider
+	output     sdk.OutputAnchor
+	iterations int
+	closedOn   []string
+}
+
+func (c *cancellationTester) Init(provider sdk.Provider) {
+	c.provider = provider
+	c.output = provider.GetOutputAnchor(`Output`)
+}
+
+func (c *cancellationTester) OnInputConnectionOpened(_ sdk.InputConnection) {}
+
+func (c *cancellationTester) OnRecordPacket(_ sdk.InputConnection) {}
+
+func (c *cancellationTester) OnComplete() {
+	info, _ := sdk.NewOutgoingRecordInfo([]sdk.NewOutgoingField{sdk.NewInt64Field(`Value`, `source`)})
+	c.output.Open(info)
+	ctx := c.provider.Context()
+	for c.iterations = 0; c.iterations < 100; c.iterations++ {
+		if ctx.Err() != nil || c.output.DownstreamClosed() {
+			break
+		}
+		info.IntFields[`Value`].SetInt(c.iterations)
+		c.output.Write()
+		c.provider.Io().UpdateProgress(float64(c.iterations) / 100)
+	}
+}
+
+func (c *cancellationTester) OnDownstreamClosed(anchor sdk.OutputAnchor) {
+	c.closedOn = append(c.closedOn, anchor.Name())
+}
+
+func TestContextCancelledByEngine(t *testing.T) {
+	implementation := &cancellationTester{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.CancelAfterProgress(3))
+	runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+
+	if implementation.iterations != 3 {
+		t.Fatalf(`expected 3 iterations but got %v`, implementation.iterations)
+	}
+	if implementation.provider.Context().Err() == nil {
+		t.Fatalf(`expected the context to be cancelled but it was not`)
+	}
+}
+
+func TestContextNotCancelledByDefault(t *testing.T) {
+	implementation := &cancellationTester{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+
+	if implementation.iterations != 100 {
+		t.Fatalf(`expected 100 iterations but got %v`, implementation.iterations)
+	}
+	if implementation.provider.Context().Err() == nil {
+		t.Fatalf(`expected the context to be released after OnComplete but it was not`)
+	}
+}
+
+func TestDownstreamClosed(t *testing.T) {
+	implementation := &cancellationTester{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.NoCache(true))
+	first := runner.CaptureOutgoingAnchor(`Output`)
+	first.StopAfter(5)
+	second := runner.CaptureOutgoingAnchor(`Output`)
+	second.StopAfter(2)
+	browse := runner.CaptureBrowseEverywhere(`Output`)
+	runner.SimulateLifecycle()
+
+	if implementation.iterations != 5 {
+		t.Fatalf(`expected 5 iterations but got %v`, implementation.iterations)
+	}
+	if expectedValues := []interface{}{0, 1, 2, 3, 4}; !reflect.DeepEqual(expectedValues, first.Data[`Value`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, first.Data[`Value`])
+	}
+	if expectedValues := []interface{}{0, 1}; !reflect.DeepEqual(expectedValues, second.Data[`Value`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, second.Data[`Value`])
+	}
+	if len(browse.Data[`Value`]) != 5 {
+		t.Fatalf(`expected 5 browse everywhere records but got %v`, len(browse.Data[`Value`]))
+	}
+	if expected := []string{`Output`}; !reflect.DeepEqual(expected, implementation.closedOn) {
+		t.Fatalf(`expected %v but got %v`, expected, implementation.closedOn)
+	}
+}
+
+func TestDownstreamClosedCached(t *testing.T) {
+	implementation := &cancellationTester{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	collector.StopAfter(3)
+	runner.SimulateLifecycle()
+
+	if expectedValues := []interface{}{0, 1, 2}; !reflect.DeepEqual(expectedValues, collector.Data[`Value`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Value`])
+	}
+	if !implementation.output.DownstreamClosed() {
+		t.Fatalf(`expected downstream to be closed but it was not`)
+	}
+}
+
+type packetWriter struct {
+	output      sdk.OutputAnchor
+	info        *sdk.OutgoingRecordInfo
+	written     int
+	closeOutput bool
+	closedOn    []string
+}
+
+func (p *packetWriter) Init(provider sdk.Provider) {
+	p.output = provider.GetOutputAnchor(`Output`)
+}
+
+func (p *packetWriter) OnInputConnectionOpened(_ sdk.InputConnection) {
+	p.info, _ = sdk.NewOutgoingRecordInfo([]sdk.NewOutgoingField{sdk.NewInt64Field(`Value`, `source`), sdk.NewV_WStringField(`Text`, `source`, 1000)})
+	p.output.Open(p.info)
+}
+
+func (p *packetWriter) OnRecordPacket(connection sdk.InputConnection) {
+	packet := connection.Read()
+	for packet.Next() {
+		if p.output.DownstreamClosed() {
+			return
+		}
+		p.info.IntFields[`Value`].SetInt(p.written)
+		p.info.StringFields[`Text`].SetString(strings.Repeat(`x`, 1000))
+		p.output.Write()
+		p.written++
+	}
+}
+
+func (p *packetWriter) OnComplete() {
+	if p.closeOutput {
+		p.output.Close()
+	}
+}
+
+func (p *packetWriter) OnDownstreamClosed(anchor sdk.OutputAnchor) {
+	p.closedOn = append(p.closedOn, anchor.Name())
+}
+
+func packetWriterInput() ([]sdk.NewOutgoingField, [][]interface{}) {
+	fields := []sdk.NewOutgoingField{sdk.NewV_WStringField(`Text`, `source`, 1000)}
+	rows := make([][]interface{}, 10000)
+	for index := range rows {
+		rows[index] = []interface{}{strings.Repeat(`x`, 1000)}
+	}
+	return fields, rows
+}
+
+func TestDownstreamClosedDetectedOnCacheWrite(t *testing.T) {
+	implementation := &packetWriter{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	collector.StopAfter(3)
+	fields, rows := packetWriterInput()
+	runner.ConnectInputData(`Input`, fields, rows)
+	runner.SimulateLifecycle()
+
+	if implementation.written == 0 || implementation.written >= 10000 {
+		t.Fatalf(`expected the tool to stop writing after the first cache write but it wrote %v records`, implementation.written)
+	}
+	if expected := []string{`Output`}; !reflect.DeepEqual(expected, implementation.closedOn) {
+		t.Fatalf(`expected %v but got %v`, expected, implementation.closedOn)
+	}
+}
+
+func TestOwnCloseIsNotDownstreamClosed(t *testing.T) {
+	for _, noCache := range []bool{false, true} {
+		implementation := &packetWriter{closeOutput: true}
+		runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.NoCache(noCache))
+		collector := runner.CaptureOutgoingAnchor(`Output`)
+		runner.ConnectInputData(`Input`, []sdk.NewOutgoingField{sdk.NewInt32Field(`Id`, `source`)}, [][]interface{}{{1}, {2}})
+		runner.SimulateLifecycle()
+
+		if len(collector.Data[`Value`]) != 2 {
+			t.Fatalf(`expected 2 records but got %v`, collector.Data[`Value`])
+		}
+		if implementation.output.DownstreamClosed() {
+			t.Fatalf(`no cache %v: expected closing the anchor to not report downstream as closed`, noCache)
+		}
+		if len(implementation.closedOn) != 0 {
+			t.Fatalf(`no cache %v: expected no notifications but got %v`, noCache, implementation.closedOn)
+		}
+	}
+}
+
+type panicTester struct {
+	output    sdk.OutputAnchor
+	panicOn   string
+	packets   int
+	completed bool
+}
+
+func (p *panicTester) Init(provider sdk.Provider) {
+	if p.panicOn == `Init` {
+		panic(`panic in Init`)
+	}
+	p.output = provider.GetOutputAnchor(`Output`)
+}
+
+func (p *panicTester) OnInputConnectionOpened(connection sdk.InputConnection) {
+	p.output.Open(connection.Metadata().Clone().GenerateOutgoingRecordInfo())
+}
+
+func (p *panicTester) OnRecordPacket(connection sdk.InputConnection) {
+	p.packets++
+	if p.panicOn == `OnRecordPacket` {
+		panic(`panic in OnRecordPacket`)
+	}
+}
+
+func (p *panicTester) OnComplete() {
+	if p.panicOn == `OnComplete` {
+		panic(`panic in OnComplete`)
+	}
+	p.completed = true
+}
+
+func TestRecoverPanicInOnRecordPacket(t *testing.T) {
+	implementation := &panicTester{panicOn: `OnRecordPacket`}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.RecoverPanics(true))
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	errors := runner.Messages(sdk.Error)
+	if len(errors) != 1 || !strings.Contains(errors[0], `panic in OnRecordPacket`) {
+		t.Fatalf(`expected a panic error message but got %v`, errors)
+	}
+	if implementation.packets != 1 {
+		t.Fatalf(`expected 1 packet but got %v`, implementation.packets)
+	}
+	if implementation.completed {
+		t.Fatalf(`expected OnComplete to be skipped but it was called`)
+	}
+	if collector.Data == nil {
+		t.Fatalf(`expected the output to be opened but it was not`)
+	}
+}
+
+func TestRecoverPanicInOnComplete(t *testing.T) {
+	implementation := &panicTester{panicOn: `OnComplete`}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.RecoverPanics(true))
+	runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	errors := runner.Messages(sdk.Error)
+	if len(errors) != 1 || !strings.Contains(errors[0], `panic in OnComplete`) {
+		t.Fatalf(`expected a panic error message but got %v`, errors)
+	}
+}
+
+func TestRecoverPanicInInit(t *testing.T) {
+	implementation := &panicTester{panicOn: `Init`}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.RecoverPanics(true))
+	runner.SimulateLifecycle()
+
+	errors := runner.Messages(sdk.Error)
+	if len(errors) != 1 || !strings.Contains(errors[0], `panic in Init`) {
+		t.Fatalf(`expected a panic error message but got %v`, errors)
+	}
+}
+
+func TestPanicsNotRecoveredByDefault(t *testing.T) {
+	defer func() {
+		if recovered := recover(); recovered == nil {
+			t.Fatalf(`expected a panic but got none`)
+		}
+	}()
+	implementation := &panicTester{panicOn: `OnComplete`}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	runner.SimulateLifecycle()
+}
+
+type errorTester struct {
+	output    sdk.OutputAnchor
+	failOn    string
+	packets   int
+	completed bool
+}
+
+func (e *errorTester) Init(provider sdk.Provider) error {
+	if e.failOn == `Init` {
+		return fmt.Errorf(`error in Init`)
+	}
+	e.output = provider.GetOutputAnchor(`Output`)
+	return nil
+}
+
+func (e *errorTester) OnInputConnectionOpened(connection sdk.InputConnection) error {
+	e.output.Open(connection.Metadata().Clone().GenerateOutgoingRecordInfo())
+	return nil
+}
+
+func (e *errorTester) OnRecordPacket(_ sdk.InputConnection) error {
+	e.packets++
+	if e.failOn == `OnRecordPacket` {
+		return fmt.Errorf(`error in OnRecordPacket`)
+	}
+	return nil
+}
+
+func (e *errorTester) OnComplete() error {
+	e.completed = true
+	return nil
+}
+
+func TestPluginErrorStopsTool(t *testing.T) {
+	implementation := &errorTester{failOn: `OnRecordPacket`}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if err := runner.Err(); err == nil || err.Error() != `error in OnRecordPacket` {
+		t.Fatalf(`expected 'error in OnRecordPacket' but got %v`, err)
+	}
+	if errors := runner.Messages(sdk.Error); len(errors) != 1 || errors[0] != `error in OnRecordPacket` {
+		t.Fatalf(`expected 1 error message but got %v`, errors)
+	}
+	if implementation.packets != 1 {
+		t.Fatalf(`expected 1 packet but got %v`, implementation.packets)
+	}
+	if implementation.completed {
+		t.Fatalf(`expected OnComplete to be skipped but it was called`)
+	}
+}
+
+func TestPluginErrorInInit(t *testing.T) {
+	implementation := &errorTester{failOn: `Init`}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if err := runner.Err(); err == nil || err.Error() != `error in Init` {
+		t.Fatalf(`expected 'error in Init' but got %v`, err)
+	}
+	if implementation.packets != 0 {
+		t.Fatalf(`expected 0 packets but got %v`, implementation.packets)
+	}
+}
+
+func TestPluginWithoutError(t *testing.T) {
+	implementation := &errorTester{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if err := runner.Err(); err != nil {
+		t.Fatalf(`expected no error but got %v`, err)
+	}
+	if !implementation.completed {
+		t.Fatalf(`expected OnComplete to be called but it was not`)
+	}
+	if collector.Data == nil {
+		t.Fatalf(`expected the output to be opened but it was not`)
+	}
+}
+
+func TestRegisterInvalidPlugin(t *testing.T) {
+	defer func() {
+		recovered := recover()
+		if recovered != `string does not implement sdk.Plugin or sdk.PluginE` {
+			t.Fatalf(`expected an invalid plugin panic but got %v`, recovered)
+		}
+	}()
+	sdk.RegisterToolTest(`not a plugin`, 1, ``)
+}
+
+type HoldRecordsTool struct {
+	output   sdk.OutputAnchor
+	metadata sdk.IncomingRecordInfo
+	records  []sdk.Record
+}
+
+func (h *HoldRecordsTool) Init(provider sdk.Provider) {
+	h.output = provider.GetOutputAnchor(`Output`)
+}
+
+func (h *HoldRecordsTool) OnInputConnectionOpened(connection sdk.InputConnection) {
+	h.metadata = connection.Metadata()
+}
+
+func (h *HoldRecordsTool) OnRecordPacket(connection sdk.InputConnection) {
+	h.records = append(h.records, h.metadata.CopyRecords(connection.Read())...)
+}
+
+func (h *HoldRecordsTool) OnComplete() {
+	info := h.metadata.Clone().GenerateOutgoingRecordInfo()
+	h.output.Open(info)
+	for index := len(h.records) - 1; index >= 0; index-- {
+		info.CopyFrom(h.records[index])
+		h.output.Write()
+	}
+}
+
+func TestCopyRecordsOutliveCallback(t *testing.T) {
+	for _, noCache := range []bool{false, true} {
+		runner := sdk.RegisterToolTest(&PassThroughTool{}, 1, ``, sdk.NoCache(noCache))
+		expected := runner.CaptureOutgoingAnchor(`Output`)
+		runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+		runner.SimulateLifecycle()
+
+		implementation := &HoldRecordsTool{}
+		runner = sdk.RegisterToolTest(implementation, 2, ``, sdk.NoCache(noCache))
+		collector := runner.CaptureOutgoingAnchor(`Output`)
+		runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+		runner.SimulateLifecycle()
+
+		if len(implementation.records) != 4 {
+			t.Fatalf(`expected 4 records but got %v`, len(implementation.records))
+		}
+		for name, values := range expected.Data {
+			reversed := make([]interface{}, len(values))
+			for index, value := range values {
+				reversed[len(values)-1-index] = value
+			}
+			if !reflect.DeepEqual(reversed, collector.Data[name]) {
+				t.Fatalf(`expected %v for %v but got %v`, reversed, name, collector.Data[name])
+			}
+		}
+	}
+}
+
+func decimalStrings(values []interface{}) []interface{} {
+	formatted := make([]interface{}, len(values))
+	for index, value := range values {
+		if decimal, ok := value.(sdk.Decimal); ok {
+			formatted[index] = decimal.String()
+			continue
+		}
+		formatted[index] = value
+	}
+	return formatted
+}
@@ -0,0 +1,276 @@
+package spatial
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+const (
+	shapeNull       = 0
+	shapePoint      = 1
+	shapePolyline   = 3
+	shapePolygon    = 5
+	shapeMultiPoint = 8
+)
+
+func Decode(blob []byte) (Geometry, error) {
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	reader := &blobReader{blob: blob}
+	shapeType := reader.int32()
+	var geometry Geometry
+	switch shapeType {
+	case shapeNull:
+		return nil, reader.err
+	case shapePoint:
+		geometry = reader.point()
+	case shapeMultiPoint:
+		reader.skip(32)
+		geometry = MultiPoint(reader.points(int(reader.int32())))
+	case shapePolyline, shapePolygon:
+		reader.skip(32)
+		parts := reader.parts()
+		if shapeType == shapePolyline {
+			geometry = polylineFromParts(parts)
+		} else {
+			geometry = polygonFromRings(parts)
+		}
+	default:
+		return nil, fmt.Errorf(`shape type %v is not supported`, shapeType)
+	}
+	if reader.err != nil {
+		return nil, reader.err
+	}
+	return geometry, nil
+}
+
+func Encode(geometry Geometry) ([]byte, error) {
+	writer := &blobWriter{}
+	switch typed := geometry.(type) {
+	case nil:
+		writer.int32(shapeNull)
+	case Point:
+		writer.int32(shapePoint)
+		writer.point(typed)
+	case MultiPoint:
+		writer.int32(shapeMultiPoint)
+		writer.bounds(typed.Bounds())
+		writer.int32(len(typed))
+		writer.points(typed)
+	case LineString:
+		writer.parts(shapePolyline, typed.Bounds(), []LineString{typed})
+	case MultiLineString:
+		writer.parts(shapePolyline, typed.Bounds(), typed)
+	case Polygon:
+		writer.parts(shapePolygon, typed.Bounds(), orientedRings(typed))
+	case MultiPolygon:
+		var rings []LineString
+		for _, polygon := range typed {
+			rings = append(rings, orientedRings(polygon)...)
+		}
+		writer.parts(shapePolygon, typed.Bounds(), rings)
+	default:
+		return nil, fmt.Errorf(`%T is not a supported geometry`, geometry)
+	}
+	return writer.blob, nil
+}
+
+func polylineFromParts(parts []LineString) Geometry {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return MultiLineString(parts)
+}
+
+func polygonFromRings(rings []LineString) Geometry {
+	if len(rings) == 0 {
+		return Polygon{}
+	}
+	exteriorClockwise := isClockwise(rings[0])
+	var polygons MultiPolygon
+	for _, ring := range rings {
+		if isClockwise(ring) == exteriorClockwise {
+			polygons = append(polygons, Polygon{ring})
+			continue
+		}
+		owner := len(polygons) - 1
+		for index, polygon := range polygons {
+			if len(ring) > 0 && ringContains(polygon[0], ring[0]) {
+				owner = index
+				break
+			}
+		}
+		polygons[owner] = append(polygons[owner], ring)
+	}
+	if len(polygons) == 1 {
+		return polygons[0]
+	}
+	return polygons
+}
+
+func orientedRings(polygon Polygon) []LineString {
+	rings := make([]LineString, len(polygon))
+	for index, ring := range polygon {
+		if isClockwise(ring) == (index == 0) {
+			rings[index] = ring
+		} else {
+			rings[index] = reversed(ring)
+		}
+	}
+	return rings
+}
+
+type blobReader struct {
+	blob     []byte
+	position int
+	err      error
+}
+
+func (r *blobReader) read(size int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if size < 0 {
+		r.err = fmt.Errorf(`spatial object has an invalid size %v`, size)
+		return nil
+	}
+	if size > len(r.blob)-r.position {
+		r.truncated(size)
+		return nil
+	}
+	bytes := r.blob[r.position : r.position+size]
+	r.position += size
+	return bytes
+}
+
+func (r *blobReader) truncated(size int) {
+	r.err = fmt.Errorf(`spatial object is truncated: expected at least %v bytes but got %v`, r.position+size, len(r.blob))
+}
+
+func (r *blobReader) skip(size int) {
+	r.read(size)
+}
+
+func (r *blobReader) int32() int {
+	bytes := r.read(4)
+	if bytes == nil {
+		return 0
+	}
+	return int(int32(binary.LittleEndian.Uint32(bytes)))
+}
+
+func (r *blobReader) float64() float64 {
+	bytes := r.read(8)
+	if bytes == nil {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(bytes))
+}
+
+func (r *blobReader) point() Point {
+	return Point{X: r.float64(), Y: r.float64()}
+}
+
+func (r *blobReader) count(count int, itemSize int) bool {
+	if r.err != nil {
+		return false
+	}
+	if count < 0 {
+		r.err = fmt.Errorf(`spatial object has an invalid count %v`, count)
+		return false
+	}
+	if count > (len(r.blob)-r.position)/itemSize {
+		r.truncated(count * itemSize)
+		return false
+	}
+	return true
+}
+
+func (r *blobReader) points(count int) []Point {
+	if !r.count(count, 16) {
+		return nil
+	}
+	points := make([]Point, count)
+	for index := range points {
+		points[index] = r.point()
+	}
+	return points
+}
+
+func (r *blobReader) parts() []LineString {
+	numParts := r.int32()
+	numPoints := r.int32()
+	if !r.count(numParts, 4) {
+		return nil
+	}
+	starts := make([]int, numParts)
+	for index := range starts {
+		starts[index] = r.int32()
+	}
+	points := r.points(numPoints)
+	if r.err != nil {
+		return nil
+	}
+	parts := make([]LineString, numParts)
+	for index, start := range starts {
+		end := numPoints
+		if index < numParts-1 {
+			end = starts[index+1]
+		}
+		if start < 0 || start > end || end > numPoints {
+			r.err = fmt.Errorf(`spatial object has an invalid part index %v`, start)
+			return nil
+		}
+		parts[index] = LineString(points[start:end])
+	}
+	return parts
+}
+
+type blobWriter struct {
+	blob []byte
+}
+
+func (w *blobWriter) int32(value int) {
+	w.blob = append(w.blob, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(w.blob[len(w.blob)-4:], uint32(int32(value)))
+}
+
+func (w *blobWriter) float64(value float64) {
+	w.blob = append(w.blob, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint64(w.blob[len(w.blob)-8:], math.Float64bits(value))
+}
+
+func (w *blobWriter) point(point Point) {
+	w.float64(point.X)
+	w.float64(point.Y)
+}
+
+func (w *blobWriter) points(points []Point) {
+	for _, point := range points {
+		w.point(point)
+	}
+}
+
+func (w *blobWriter) bounds(bounds Bounds) {
+	w.float64(bounds.MinX)
+	w.float64(bounds.MinY)
+	w.float64(bounds.MaxX)
+	w.float64(bounds.MaxY)
+}
+
+func (w *blobWriter) parts(shapeType int, bounds Bounds, parts []LineString) {
+	w.int32(shapeType)
+	w.bounds(bounds)
+	w.int32(len(parts))
+	w.int32(len(flatten(parts)))
+	start := 0
+	for _, part := range parts {
+		w.int32(start)
+		start += len(part)
+	}
+	for _, part := range parts {
+		w.points(part)
+	}
+}
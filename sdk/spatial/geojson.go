@@ -0,0 +1,151 @@
+package spatial
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type geoJson struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates,omitempty"`
+	Geometry    json.RawMessage `json:"geometry,omitempty"`
+}
+
+func ToGeoJson(geometry Geometry) ([]byte, error) {
+	var geometryType string
+	var coordinates interface{}
+	switch typed := geometry.(type) {
+	case Point:
+		geometryType, coordinates = `Point`, geoJsonPoint(typed)
+	case MultiPoint:
+		geometryType, coordinates = `MultiPoint`, geoJsonPoints(typed)
+	case LineString:
+		geometryType, coordinates = `LineString`, geoJsonPoints(typed)
+	case MultiLineString:
+		geometryType, coordinates = `MultiLineString`, geoJsonLines(typed)
+	case Polygon:
+		geometryType, coordinates = `Polygon`, geoJsonLines(typed)
+	case MultiPolygon:
+		polygons := make([][][][2]float64, len(typed))
+		for index, polygon := range typed {
+			polygons[index] = geoJsonLines(polygon)
+		}
+		geometryType, coordinates = `MultiPolygon`, polygons
+	default:
+		return nil, fmt.Errorf(`%T is not a supported geometry`, geometry)
+	}
+	return json.Marshal(struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+	}{Type: geometryType, Coordinates: coordinates})
+}
+
+func geoJsonPoint(point Point) [2]float64 {
+	return [2]float64{point.X, point.Y}
+}
+
+func geoJsonPoints(points []Point) [][2]float64 {
+	coordinates := make([][2]float64, len(points))
+	for index, point := range points {
+		coordinates[index] = geoJsonPoint(point)
+	}
+	return coordinates
+}
+
+func geoJsonLines(lines []LineString) [][][2]float64 {
+	coordinates := make([][][2]float64, len(lines))
+	for index, line := range lines {
+		coordinates[index] = geoJsonPoints(line)
+	}
+	return coordinates
+}
+
+func FromGeoJson(data []byte) (Geometry, error) {
+	object := geoJson{}
+	err := json.Unmarshal(data, &object)
+	if err != nil {
+		return nil, fmt.Errorf(`invalid GeoJSON: %v`, err.Error())
+	}
+	if object.Type == `Feature` {
+		if len(object.Geometry) == 0 || string(object.Geometry) == `null` {
+			return nil, nil
+		}
+		return FromGeoJson(object.Geometry)
+	}
+
+	var geometry Geometry
+	switch object.Type {
+	case `Point`:
+		var coordinates []float64
+		err = json.Unmarshal(object.Coordinates, &coordinates)
+		if err == nil && len(coordinates) < 2 {
+			err = fmt.Errorf(`a position needs at least 2 coordinates`)
+		}
+		if err == nil {
+			geometry = Point{X: coordinates[0], Y: coordinates[1]}
+		}
+	case `MultiPoint`, `LineString`:
+		var coordinates [][]float64
+		err = json.Unmarshal(object.Coordinates, &coordinates)
+		var points []Point
+		if err == nil {
+			points, err = pointsFromGeoJson(coordinates)
+		}
+		if object.Type == `MultiPoint` {
+			geometry = MultiPoint(points)
+		} else {
+			geometry = LineString(points)
+		}
+	case `MultiLineString`, `Polygon`:
+		var coordinates [][][]float64
+		err = json.Unmarshal(object.Coordinates, &coordinates)
+		var lines []LineString
+		if err == nil {
+			lines, err = linesFromGeoJson(coordinates)
+		}
+		if object.Type == `MultiLineString` {
+			geometry = MultiLineString(lines)
+		} else {
+			geometry = Polygon(lines)
+		}
+	case `MultiPolygon`:
+		var coordinates [][][][]float64
+		err = json.Unmarshal(object.Coordinates, &coordinates)
+		polygons := make(MultiPolygon, len(coordinates))
+		for index := 0; err == nil && index < len(coordinates); index++ {
+			var lines []LineString
+			lines, err = linesFromGeoJson(coordinates[index])
+			polygons[index] = lines
+		}
+		geometry = polygons
+	default:
+		return nil, fmt.Errorf(`GeoJSON type '%v' is not supported`, object.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(`invalid GeoJSON %v: %v`, object.Type, err.Error())
+	}
+	return geometry, nil
+}
+
+func pointsFromGeoJson(coordinates [][]float64) ([]Point, error) {
+	points := make([]Point, len(coordinates))
+	for index, position := range coordinates {
+		if len(position) < 2 {
+			return nil, fmt.Errorf(`a position needs at least 2 coordinates`)
+		}
+		points[index] = Point{X: position[0], Y: position[1]}
+	}
+	return points, nil
+}
+
+func linesFromGeoJson(coordinates [][][]float64) ([]LineString, error) {
+	lines := make([]LineString, len(coordinates))
+	for index, line := range coordinates {
+		points, err := pointsFromGeoJson(line)
+		if err != nil {
+			return nil, err
+		}
+		lines[index] = points
+	}
+	return lines, nil
+}
@@ -0,0 +1,110 @@
+package spatial
+
+import "math"
+
+type Geometry interface {
+	Bounds() Bounds
+}
+
+type Bounds struct {
+	MinX float64
+	MinY float64
+	MaxX float64
+	MaxY float64
+}
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+type MultiPoint []Point
+
+type LineString []Point
+
+type MultiLineString []LineString
+
+type Polygon []LineString
+
+type MultiPolygon []Polygon
+
+func (p Point) Bounds() Bounds {
+	return Bounds{MinX: p.X, MinY: p.Y, MaxX: p.X, MaxY: p.Y}
+}
+
+func (m MultiPoint) Bounds() Bounds {
+	return pointBounds(m)
+}
+
+func (l LineString) Bounds() Bounds {
+	return pointBounds(l)
+}
+
+func (m MultiLineString) Bounds() Bounds {
+	return pointBounds(flatten(m))
+}
+
+func (p Polygon) Bounds() Bounds {
+	return pointBounds(flatten(p))
+}
+
+func (m MultiPolygon) Bounds() Bounds {
+	var rings []LineString
+	for _, polygon := range m {
+		rings = append(rings, polygon...)
+	}
+	return pointBounds(flatten(rings))
+}
+
+func pointBounds(points []Point) Bounds {
+	if len(points) == 0 {
+		return Bounds{}
+	}
+	bounds := Bounds{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+	for _, point := range points {
+		bounds.MinX = math.Min(bounds.MinX, point.X)
+		bounds.MinY = math.Min(bounds.MinY, point.Y)
+		bounds.MaxX = math.Max(bounds.MaxX, point.X)
+		bounds.MaxY = math.Max(bounds.MaxY, point.Y)
+	}
+	return bounds
+}
+
+func flatten(lines []LineString) []Point {
+	var points []Point
+	for _, line := range lines {
+		points = append(points, line...)
+	}
+	return points
+}
+
+func signedArea(ring LineString) float64 {
+	area := 0.0
+	for index := 0; index < len(ring)-1; index++ {
+		area += ring[index].X*ring[index+1].Y - ring[index+1].X*ring[index].Y
+	}
+	return area / 2
+}
+
+func isClockwise(ring LineString) bool {
+	return signedArea(ring) < 0
+}
+
+func reversed(ring LineString) LineString {
+	result := make(LineString, len(ring))
+	for index, point := range ring {
+		result[len(ring)-1-index] = point
+	}
+	return result
+}
+
+func ringContains(ring LineString, point Point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		if (ring[i].Y > point.Y) != (ring[j].Y > point.Y) &&
+			point.X < (ring[j].X-ring[i].X)*(point.Y-ring[i].Y)/(ring[j].Y-ring[i].Y)+ring[i].X {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,163 @@
+package spatial_test
+
+import (
+	"encoding/binary"
+	"github.com/tlarsendataguy/goalteryx/sdk/spatial"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var square = spatial.LineString{{X: 0, Y: 0}, {X: 0, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 0}, {X: 0, Y: 0}}
+var hole = spatial.LineString{{X: 2, Y: 2}, {X: 4, Y: 2}, {X: 4, Y: 4}, {X: 2, Y: 4}, {X: 2, Y: 2}}
+var farSquare = spatial.LineString{{X: 20, Y: 20}, {X: 20, Y: 30}, {X: 30, Y: 30}, {X: 30, Y: 20}, {X: 20, Y: 20}}
+
+var geometries = []spatial.Geometry{
+	spatial.Point{X: 1.5, Y: -2},
+	spatial.MultiPoint{{X: 1, Y: 2}, {X: 3, Y: 4}},
+	spatial.LineString{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 0}},
+	spatial.MultiLineString{{{X: 0, Y: 0}, {X: 1, Y: 1}}, {{X: 5, Y: 5}, {X: 6, Y: 7}}},
+	spatial.Polygon{square, hole},
+	spatial.MultiPolygon{{square, hole}, {farSquare}},
+}
+
+func TestBlobRoundTrip(t *testing.T) {
+	for _, geometry := range geometries {
+		blob, err := spatial.Encode(geometry)
+		if err != nil {
+			t.Fatalf(`expected no error encoding %v but got: %v`, geometry, err.Error())
+		}
+		decoded, err := spatial.Decode(blob)
+		if err != nil {
+			t.Fatalf(`expected no error decoding %v but got: %v`, geometry, err.Error())
+		}
+		if !reflect.DeepEqual(geometry, decoded) {
+			t.Fatalf(`expected %v but got %v`, geometry, decoded)
+		}
+	}
+}
+
+func TestEncodeOrientsRings(t *testing.T) {
+	counterClockwise := spatial.LineString{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0}}
+	clockwiseHole := spatial.LineString{{X: 2, Y: 2}, {X: 2, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 2}, {X: 2, Y: 2}}
+	blob, err := spatial.Encode(spatial.Polygon{counterClockwise, clockwiseHole})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	decoded, err := spatial.Decode(blob)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	expected := spatial.Polygon{square, hole}
+	if !reflect.DeepEqual(expected, decoded) {
+		t.Fatalf(`expected %v but got %v`, expected, decoded)
+	}
+	if bounds := decoded.Bounds(); bounds != (spatial.Bounds{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}) {
+		t.Fatalf(`expected bounds 0,0,10,10 but got %v`, bounds)
+	}
+}
+
+func TestDecodeNullAndInvalidBlobs(t *testing.T) {
+	geometry, err := spatial.Decode(nil)
+	if geometry != nil || err != nil {
+		t.Fatalf(`expected nil geometry and no error but got %v and %v`, geometry, err)
+	}
+	blob, _ := spatial.Encode(spatial.Polygon{square})
+	_, err = spatial.Decode(blob[:len(blob)-3])
+	if err == nil || !strings.Contains(err.Error(), `truncated`) {
+		t.Fatalf(`expected a truncated error but got %v`, err)
+	}
+	_, err = spatial.Decode([]byte{31, 0, 0, 0})
+	if err == nil || err.Error() != `shape type 31 is not supported` {
+		t.Fatalf(`expected an unsupported shape error but got %v`, err)
+	}
+}
+
+func TestDecodeInvalidCounts(t *testing.T) {
+	multiPoint, _ := spatial.Encode(spatial.MultiPoint{{X: 1, Y: 2}})
+	polyline, _ := spatial.Encode(spatial.LineString{{X: 0, Y: 0}, {X: 1, Y: 1}})
+	setInt32 := func(blob []byte, offset int, value int32) []byte {
+		corrupt := append([]byte{}, blob...)
+		binary.LittleEndian.PutUint32(corrupt[offset:], uint32(value))
+		return corrupt
+	}
+	for name, blob := range map[string][]byte{
+		`negative point count`: setInt32(multiPoint, 36, -1),
+		`huge point count`:     setInt32(multiPoint, 36, math.MaxInt32),
+		`negative part count`:  setInt32(polyline, 36, -1),
+		`huge part count`:      setInt32(polyline, 36, math.MaxInt32),
+		`negative points`:      setInt32(polyline, 40, -2),
+		`negative part start`:  setInt32(polyline, 44, -5),
+	} {
+		geometry, err := spatial.Decode(blob)
+		if err == nil {
+			t.Fatalf(`expected an error for %v but got %v`, name, geometry)
+		}
+	}
+}
+
+func TestWkt(t *testing.T) {
+	expected := []string{
+		`POINT (1.5 -2)`,
+		`MULTIPOINT ((1 2), (3 4))`,
+		`LINESTRING (0 0, 1 1, 2 0)`,
+		`MULTILINESTRING ((0 0, 1 1), (5 5, 6 7))`,
+		`POLYGON ((0 0, 0 10, 10 10, 10 0, 0 0), (2 2, 4 2, 4 4, 2 4, 2 2))`,
+		`MULTIPOLYGON (((0 0, 0 10, 10 10, 10 0, 0 0), (2 2, 4 2, 4 4, 2 4, 2 2)), ((20 20, 20 30, 30 30, 30 20, 20 20)))`,
+	}
+	for index, geometry := range geometries {
+		wkt, err := spatial.ToWkt(geometry)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+		if wkt != expected[index] {
+			t.Fatalf(`expected '%v' but got '%v'`, expected[index], wkt)
+		}
+		parsed, err := spatial.FromWkt(wkt)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+		if !reflect.DeepEqual(geometry, parsed) {
+			t.Fatalf(`expected %v but got %v`, geometry, parsed)
+		}
+	}
+
+	parsed, err := spatial.FromWkt(`multipoint (1 2, 3 4)`)
+	if err != nil || !reflect.DeepEqual(geometries[1], parsed) {
+		t.Fatalf(`expected %v but got %v and %v`, geometries[1], parsed, err)
+	}
+	_, err = spatial.FromWkt(`LINESTRING (0 0, 1)`)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
+
+func TestGeoJson(t *testing.T) {
+	for _, geometry := range geometries {
+		data, err := spatial.ToGeoJson(geometry)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+		parsed, err := spatial.FromGeoJson(data)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+		if !reflect.DeepEqual(geometry, parsed) {
+			t.Fatalf(`expected %v but got %v`, geometry, parsed)
+		}
+	}
+
+	data, _ := spatial.ToGeoJson(geometries[0])
+	if expected := `{"type":"Point","coordinates":[1.5,-2]}`; string(data) != expected {
+		t.Fatalf(`expected %v but got %v`, expected, string(data))
+	}
+	parsed, err := spatial.FromGeoJson([]byte(`{"type":"Feature","properties":{},"geometry":{"type":"Point","coordinates":[1,2,3]}}`))
+	if err != nil || parsed != (spatial.Point{X: 1, Y: 2}) {
+		t.Fatalf(`expected POINT (1 2) but got %v and %v`, parsed, err)
+	}
+	_, err = spatial.FromGeoJson([]byte(`{"type":"Circle"}`))
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
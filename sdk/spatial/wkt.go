@@ -0,0 +1,247 @@
+package spatial
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func ToWkt(geometry Geometry) (string, error) {
+	switch typed := geometry.(type) {
+	case Point:
+		return `POINT (` + wktPoint(typed) + `)`, nil
+	case MultiPoint:
+		if len(typed) == 0 {
+			return `MULTIPOINT EMPTY`, nil
+		}
+		points := make([]string, len(typed))
+		for index, point := range typed {
+			points[index] = `(` + wktPoint(point) + `)`
+		}
+		return `MULTIPOINT (` + strings.Join(points, `, `) + `)`, nil
+	case LineString:
+		return `LINESTRING ` + wktLine(typed), nil
+	case MultiLineString:
+		return `MULTILINESTRING ` + wktLines(typed), nil
+	case Polygon:
+		return `POLYGON ` + wktLines(typed), nil
+	case MultiPolygon:
+		if len(typed) == 0 {
+			return `MULTIPOLYGON EMPTY`, nil
+		}
+		polygons := make([]string, len(typed))
+		for index, polygon := range typed {
+			polygons[index] = wktLines(polygon)
+		}
+		return `MULTIPOLYGON (` + strings.Join(polygons, `, `) + `)`, nil
+	default:
+		return ``, fmt.Errorf(`%T is not a supported geometry`, geometry)
+	}
+}
+
+func wktPoint(point Point) string {
+	return strconv.FormatFloat(point.X, 'f', -1, 64) + ` ` + strconv.FormatFloat(point.Y, 'f', -1, 64)
+}
+
+func wktLine(line LineString) string {
+	if len(line) == 0 {
+		return `EMPTY`
+	}
+	points := make([]string, len(line))
+	for index, point := range line {
+		points[index] = wktPoint(point)
+	}
+	return `(` + strings.Join(points, `, `) + `)`
+}
+
+func wktLines(lines []LineString) string {
+	if len(lines) == 0 {
+		return `EMPTY`
+	}
+	parts := make([]string, len(lines))
+	for index, line := range lines {
+		parts[index] = wktLine(line)
+	}
+	return `(` + strings.Join(parts, `, `) + `)`
+}
+
+func FromWkt(wkt string) (Geometry, error) {
+	parser := &wktParser{tokens: tokenizeWkt(wkt)}
+	geometry := parser.geometry()
+	if parser.err == nil && parser.position < len(parser.tokens) {
+		parser.fail(`unexpected '%v'`, parser.tokens[parser.position])
+	}
+	if parser.err != nil {
+		return nil, fmt.Errorf(`invalid WKT '%v': %v`, wkt, parser.err.Error())
+	}
+	return geometry, nil
+}
+
+func tokenizeWkt(wkt string) []string {
+	var tokens []string
+	current := strings.Builder{}
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, char := range wkt {
+		switch char {
+		case '(', ')', ',':
+			flush()
+			tokens = append(tokens, string(char))
+		case ' ', '\t', '\r', '\n':
+			flush()
+		default:
+			current.WriteRune(char)
+		}
+	}
+	flush()
+	return tokens
+}
+
+type wktParser struct {
+	tokens   []string
+	position int
+	err      error
+}
+
+func (p *wktParser) fail(format string, args ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (p *wktParser) peek() string {
+	if p.err != nil || p.position >= len(p.tokens) {
+		return ``
+	}
+	return p.tokens[p.position]
+}
+
+func (p *wktParser) next() string {
+	token := p.peek()
+	if token == `` {
+		p.fail(`unexpected end of text`)
+		return ``
+	}
+	p.position++
+	return token
+}
+
+func (p *wktParser) expect(token string) {
+	if actual := p.next(); actual != token && p.err == nil {
+		p.fail(`expected '%v' but got '%v'`, token, actual)
+	}
+}
+
+func (p *wktParser) empty() bool {
+	if strings.EqualFold(p.peek(), `EMPTY`) {
+		p.position++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) geometry() Geometry {
+	geometryType := strings.ToUpper(p.next())
+	switch geometryType {
+	case `POINT`:
+		if p.empty() {
+			p.fail(`empty points are not supported`)
+			return nil
+		}
+		p.expect(`(`)
+		point := p.point()
+		p.expect(`)`)
+		return point
+	case `MULTIPOINT`:
+		if p.empty() {
+			return MultiPoint{}
+		}
+		var points MultiPoint
+		p.list(func() {
+			if p.peek() == `(` {
+				p.expect(`(`)
+				points = append(points, p.point())
+				p.expect(`)`)
+				return
+			}
+			points = append(points, p.point())
+		})
+		return points
+	case `LINESTRING`:
+		return p.line()
+	case `MULTILINESTRING`:
+		return MultiLineString(p.lines())
+	case `POLYGON`:
+		return Polygon(p.lines())
+	case `MULTIPOLYGON`:
+		if p.empty() {
+			return MultiPolygon{}
+		}
+		var polygons MultiPolygon
+		p.list(func() {
+			polygons = append(polygons, p.lines())
+		})
+		return polygons
+	default:
+		if p.err == nil {
+			p.fail(`'%v' is not a supported geometry type`, geometryType)
+		}
+		return nil
+	}
+}
+
+func (p *wktParser) list(item func()) {
+	p.expect(`(`)
+	for p.err == nil {
+		item()
+		if p.peek() != `,` {
+			break
+		}
+		p.position++
+	}
+	p.expect(`)`)
+}
+
+func (p *wktParser) point() Point {
+	x := p.number()
+	y := p.number()
+	for p.err == nil && p.peek() != `,` && p.peek() != `)` && p.peek() != `` {
+		p.number()
+	}
+	return Point{X: x, Y: y}
+}
+
+func (p *wktParser) number() float64 {
+	token := p.next()
+	value, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		p.fail(`expected a number but got '%v'`, token)
+	}
+	return value
+}
+
+func (p *wktParser) line() LineString {
+	if p.empty() {
+		return LineString{}
+	}
+	var line LineString
+	p.list(func() {
+		line = append(line, p.point())
+	})
+	return line
+}
+
+func (p *wktParser) lines() []LineString {
+	if p.empty() {
+		return []LineString{}
+	}
+	var lines []LineString
+	p.list(func() {
+		lines = append(lines, p.line())
+	})
+	return lines
+}
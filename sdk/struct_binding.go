@@ -0,0 +1,530 @@
+package sdk
+
+import (
+	"fmt"
+	c "github.com/tlarsendataguy/goalteryx/sdk/config_tag"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const structTag = c.StructTag
+
+var timeType = reflect.TypeOf(time.Time{})
+var decimalType = reflect.TypeOf(Decimal{})
+var bytesType = reflect.TypeOf([]byte{})
+
+type structFieldTag struct {
+	name     string
+	typeName string
+	size     int
+	scale    int
+}
+
+type boundField struct {
+	index []int
+	tag   structFieldTag
+	typ   reflect.Type
+}
+
+func parseStructFields(structType reflect.Type) ([]boundField, error) {
+	var fields []boundField
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if field.PkgPath != `` {
+			continue
+		}
+		tag, skip, err := parseStructFieldTag(field)
+		if err != nil {
+			return nil, err
+		}
+		if skip {
+			continue
+		}
+		fields = append(fields, boundField{index: field.Index, tag: tag, typ: field.Type})
+	}
+	return fields, nil
+}
+
+func parseStructFieldTag(field reflect.StructField) (structFieldTag, bool, error) {
+	tagValue := field.Tag.Get(structTag)
+	if tagValue == `-` {
+		return structFieldTag{}, true, nil
+	}
+	parts := strings.Split(tagValue, `,`)
+	tag := structFieldTag{name: parts[0]}
+	if tag.name == `` {
+		tag.name = field.Name
+	}
+	for _, part := range parts[1:] {
+		keyValue := strings.SplitN(part, `=`, 2)
+		if len(keyValue) != 2 {
+			return tag, false, fmt.Errorf(`invalid option '%v' in the tag of struct field '%v'`, part, field.Name)
+		}
+		var err error
+		switch strings.TrimSpace(keyValue[0]) {
+		case `type`:
+			tag.typeName = strings.TrimSpace(keyValue[1])
+		case `size`:
+			tag.size, err = strconv.Atoi(strings.TrimSpace(keyValue[1]))
+		case `scale`:
+			tag.scale, err = strconv.Atoi(strings.TrimSpace(keyValue[1]))
+		default:
+			return tag, false, fmt.Errorf(`invalid option '%v' in the tag of struct field '%v'`, part, field.Name)
+		}
+		if err != nil {
+			return tag, false, fmt.Errorf(`invalid option '%v' in the tag of struct field '%v': %v`, part, field.Name, err.Error())
+		}
+	}
+	return tag, false, nil
+}
+
+func structTypeOf(value interface{}) (reflect.Type, error) {
+	structType := reflect.TypeOf(value)
+	if structType == nil {
+		return nil, fmt.Errorf(`expected a struct or a pointer to a struct but got nil`)
+	}
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(`expected a struct or a pointer to a struct but got %v`, reflect.TypeOf(value))
+	}
+	return structType, nil
+}
+
+type RecordBinder struct {
+	dest    reflect.Value
+	setters []func(Record, reflect.Value)
+}
+
+func (i IncomingRecordInfo) Bind(dest interface{}) (*RecordBinder, error) {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() || destValue.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf(`Bind requires a non-nil pointer to a struct but got %v`, reflect.TypeOf(dest))
+	}
+	fields, err := parseStructFields(destValue.Elem().Type())
+	if err != nil {
+		return nil, err
+	}
+	binder := &RecordBinder{dest: destValue.Elem()}
+	for _, field := range fields {
+		setter, err := i.generateStructSetter(field)
+		if err != nil {
+			return nil, err
+		}
+		binder.setters = append(binder.setters, setter)
+	}
+	return binder, nil
+}
+
+func (b *RecordBinder) Scan(record Record) {
+	for _, setter := range b.setters {
+		setter(record, b.dest)
+	}
+}
+
+func (b *RecordBinder) ScanAll(packet RecordPacket, dest interface{}) error {
+	sliceValue := reflect.ValueOf(dest)
+	if sliceValue.Kind() != reflect.Ptr || sliceValue.Elem().Kind() != reflect.Slice || sliceValue.Elem().Type().Elem() != b.dest.Type() {
+		return fmt.Errorf(`ScanAll requires a pointer to a slice of %v but got %v`, b.dest.Type(), reflect.TypeOf(dest))
+	}
+	slice := sliceValue.Elem()
+	for packet.Next() {
+		b.Scan(packet.Record())
+		slice.Set(reflect.Append(slice, b.dest))
+	}
+	return nil
+}
+
+func (i IncomingRecordInfo) generateStructSetter(field boundField) (func(Record, reflect.Value), error) {
+	var incoming *IncomingField
+	for index := range i.fields {
+		if i.fields[index].Name == field.tag.name {
+			incoming = &i.fields[index]
+			break
+		}
+	}
+	if incoming == nil {
+		return nil, fmt.Errorf(`there is no '%v' field in the record`, field.tag.name)
+	}
+
+	valueType := field.typ
+	isPtr := valueType.Kind() == reflect.Ptr
+	if isPtr {
+		valueType = valueType.Elem()
+	}
+	wrongType := fmt.Errorf(`the '%v' field is '%v' and cannot be bound to a struct field of type %v`, incoming.Name, incoming.Type, field.typ)
+
+	var extract func(Record) (reflect.Value, bool)
+	switch incoming.Type {
+	case `Bool`:
+		if valueType.Kind() != reflect.Bool {
+			return nil, wrongType
+		}
+		getter := generateBoolField(*incoming).GetValue
+		extract = func(record Record) (reflect.Value, bool) {
+			value, isNull := getter(record)
+			return reflect.ValueOf(value).Convert(valueType), isNull
+		}
+	case `Byte`, `Int16`, `Int32`, `Int64`:
+		if !isNumericKind(valueType.Kind()) {
+			return nil, wrongType
+		}
+		intField, _ := i.GetIntField(incoming.Name)
+		getter := intField.GetValue
+		extract = func(record Record) (reflect.Value, bool) {
+			value, isNull := getter(record)
+			return reflect.ValueOf(value).Convert(valueType), isNull
+		}
+	case `Float`, `Double`, `FixedDecimal`:
+		if incoming.Type == `FixedDecimal` && valueType == decimalType {
+			getter := generateDecimalField(*incoming).GetValue
+			extract = func(record Record) (reflect.Value, bool) {
+				value, isNull := getter(record)
+				return reflect.ValueOf(value), isNull
+			}
+			break
+		}
+		if !isFloatKind(valueType.Kind()) {
+			return nil, wrongType
+		}
+		floatField, _ := i.GetFloatField(incoming.Name)
+		getter := floatField.GetValue
+		extract = func(record Record) (reflect.Value, bool) {
+			value, isNull := getter(record)
+			return reflect.ValueOf(value).Convert(valueType), isNull
+		}
+	case `String`, `WString`, `V_String`, `V_WString`:
+		if valueType.Kind() != reflect.String {
+			return nil, wrongType
+		}
+		stringField, _ := i.GetStringField(incoming.Name)
+		getter := stringField.GetValue
+		extract = func(record Record) (reflect.Value, bool) {
+			value, isNull := getter(record)
+			return reflect.ValueOf(value).Convert(valueType), isNull
+		}
+	case `Date`, `DateTime`, `Time`:
+		if valueType != timeType {
+			return nil, wrongType
+		}
+		timeField, _ := i.GetTimeField(incoming.Name)
+		getter := timeField.GetValue
+		extract = func(record Record) (reflect.Value, bool) {
+			value, isNull := getter(record)
+			return reflect.ValueOf(value), isNull
+		}
+	case `Blob`, `SpatialObj`:
+		if valueType != bytesType {
+			return nil, wrongType
+		}
+		getter := incoming.GetBytes
+		extract = func(record Record) (reflect.Value, bool) {
+			value := getter(record)
+			if value == nil {
+				return reflect.Value{}, true
+			}
+			copyValue := make([]byte, len(value))
+			copy(copyValue, value)
+			return reflect.ValueOf(copyValue), false
+		}
+	default:
+		return nil, wrongType
+	}
+
+	index := field.index
+	return func(record Record, dest reflect.Value) {
+		target := dest.FieldByIndex(index)
+		value, isNull := extract(record)
+		if isNull {
+			target.Set(reflect.Zero(target.Type()))
+			return
+		}
+		if isPtr {
+			pointer := reflect.New(valueType)
+			pointer.Elem().Set(value)
+			target.Set(pointer)
+			return
+		}
+		target.Set(value)
+	}, nil
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	return c.IsIntKind(kind)
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return c.IsFloatKind(kind)
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	return isIntKind(kind) || isFloatKind(kind)
+}
+
+func NewOutgoingRecordInfoFromStruct(value interface{}, source string) (*OutgoingRecordInfo, error) {
+	structType, err := structTypeOf(value)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseStructFields(structType)
+	if err != nil {
+		return nil, err
+	}
+	newFields := make([]NewOutgoingField, len(fields))
+	for index, field := range fields {
+		newField, err := generateOutgoingFieldForStruct(field, source)
+		if err != nil {
+			return nil, err
+		}
+		newFields[index] = newField
+	}
+	info, _ := NewOutgoingRecordInfo(newFields)
+	return info, nil
+}
+
+func generateOutgoingFieldForStruct(field boundField, source string) (NewOutgoingField, error) {
+	valueType := field.typ
+	if valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	tag := field.tag
+	typeName := tag.typeName
+	if typeName == `` {
+		switch {
+		case valueType == timeType:
+			typeName = `DateTime`
+		case valueType == decimalType:
+			typeName = `FixedDecimal`
+		case valueType == bytesType:
+			typeName = `Blob`
+		default:
+			switch valueType.Kind() {
+			case reflect.Bool:
+				typeName = `Bool`
+			case reflect.Uint8:
+				typeName = `Byte`
+			case reflect.Int8, reflect.Int16:
+				typeName = `Int16`
+			case reflect.Uint16, reflect.Int32:
+				typeName = `Int32`
+			case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+				typeName = `Int64`
+			case reflect.Float32:
+				typeName = `Float`
+			case reflect.Float64:
+				typeName = `Double`
+			case reflect.String:
+				typeName = `V_WString`
+			default:
+				return nil, fmt.Errorf(`struct field '%v' has type %v, which cannot be converted to an Alteryx field`, tag.name, field.typ)
+			}
+		}
+	}
+
+	size := tag.size
+	switch typeName {
+	case `Bool`:
+		return NewBoolField(tag.name, source), nil
+	case `Byte`:
+		return NewByteField(tag.name, source), nil
+	case `Int16`:
+		return NewInt16Field(tag.name, source), nil
+	case `Int32`:
+		return NewInt32Field(tag.name, source), nil
+	case `Int64`:
+		return NewInt64Field(tag.name, source), nil
+	case `Float`:
+		return NewFloatField(tag.name, source), nil
+	case `Double`:
+		return NewDoubleField(tag.name, source), nil
+	case `FixedDecimal`:
+		if size == 0 {
+			return nil, fmt.Errorf(`struct field '%v' is a FixedDecimal and requires a size in its tag`, tag.name)
+		}
+		return NewFixedDecimalField(tag.name, source, size, tag.scale), nil
+	case `Date`:
+		return NewDateField(tag.name, source), nil
+	case `DateTime`:
+		return NewDateTimeField(tag.name, source), nil
+	case `Time`:
+		return NewTimeField(tag.name, source), nil
+	case `String`, `WString`:
+		if size == 0 {
+			return nil, fmt.Errorf(`struct field '%v' is a %v and requires a size in its tag`, tag.name, typeName)
+		}
+		if typeName == `String` {
+			return NewStringField(tag.name, source, size), nil
+		}
+		return NewWStringField(tag.name, source, size), nil
+	case `V_String`:
+		if size == 0 {
+			size = 2147483647
+		}
+		return NewV_StringField(tag.name, source, size), nil
+	case `V_WString`:
+		if size == 0 {
+			size = 1073741823
+		}
+		return NewV_WStringField(tag.name, source, size), nil
+	case `Blob`:
+		if size == 0 {
+			size = 2147483647
+		}
+		return NewBlobField(tag.name, source, size), nil
+	case `SpatialObj`:
+		if size == 0 {
+			size = 2147483647
+		}
+		return NewSpatialObjField(tag.name, source, size), nil
+	default:
+		return nil, fmt.Errorf(`struct field '%v' has an invalid field type '%v'`, tag.name, typeName)
+	}
+}
+
+func (i *OutgoingRecordInfo) SetFromStruct(value interface{}) error {
+	structValue := reflect.ValueOf(value)
+	if structValue.Kind() == reflect.Ptr {
+		if structValue.IsNil() {
+			return fmt.Errorf(`expected a struct or a pointer to a struct but got nil`)
+		}
+		structValue = structValue.Elem()
+	}
+	if structValue.Kind() != reflect.Struct {
+		return fmt.Errorf(`expected a struct or a pointer to a struct but got %v`, reflect.TypeOf(value))
+	}
+	setters, err := i.structSetters(structValue.Type())
+	if err != nil {
+		return err
+	}
+	for _, setter := range setters {
+		if err := setter(structValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *OutgoingRecordInfo) structSetters(structType reflect.Type) ([]func(reflect.Value) error, error) {
+	if setters, ok := i.structSetterCache[structType]; ok {
+		return setters, nil
+	}
+	fields, err := parseStructFields(structType)
+	if err != nil {
+		return nil, err
+	}
+	setters := make([]func(reflect.Value) error, 0, len(fields))
+	for _, field := range fields {
+		setter, err := i.generateStructGetter(field)
+		if err != nil {
+			return nil, err
+		}
+		setters = append(setters, setter)
+	}
+	if i.structSetterCache == nil {
+		i.structSetterCache = make(map[reflect.Type][]func(reflect.Value) error)
+	}
+	i.structSetterCache[structType] = setters
+	return setters, nil
+}
+
+func (i *OutgoingRecordInfo) generateStructGetter(field boundField) (func(reflect.Value) error, error) {
+	var outgoing *outgoingField
+	for _, candidate := range i.outgoingFields {
+		if candidate.Name == field.tag.name {
+			outgoing = candidate
+			break
+		}
+	}
+	if outgoing == nil {
+		return nil, fmt.Errorf(`there is no '%v' field in the record`, field.tag.name)
+	}
+
+	valueType := field.typ
+	isPtr := valueType.Kind() == reflect.Ptr
+	if isPtr {
+		valueType = valueType.Elem()
+	}
+	wrongType := fmt.Errorf(`the '%v' field is '%v' and cannot be set from a struct field of type %v`, outgoing.Name, outgoing.Type, field.typ)
+
+	var set func(reflect.Value) error
+	switch outgoing.Type {
+	case `Bool`:
+		if valueType.Kind() != reflect.Bool {
+			return nil, wrongType
+		}
+		set = func(value reflect.Value) error {
+			outgoing.SetBool(value.Bool())
+			return nil
+		}
+	case `Byte`, `Int16`, `Int32`, `Int64`:
+		switch {
+		case isIntKind(valueType.Kind()):
+			set = func(value reflect.Value) error {
+				outgoing.SetInt(int(value.Convert(reflect.TypeOf(int64(0))).Int()))
+				return nil
+			}
+		default:
+			return nil, wrongType
+		}
+	case `Float`, `Double`, `FixedDecimal`:
+		switch {
+		case outgoing.Type == `FixedDecimal` && valueType == decimalType:
+			set = func(value reflect.Value) error {
+				return outgoing.SetDecimal(value.Interface().(Decimal))
+			}
+		case isNumericKind(valueType.Kind()):
+			set = func(value reflect.Value) error {
+				outgoing.SetFloat(value.Convert(reflect.TypeOf(float64(0))).Float())
+				return nil
+			}
+		default:
+			return nil, wrongType
+		}
+	case `String`, `WString`, `V_String`, `V_WString`:
+		if valueType.Kind() != reflect.String {
+			return nil, wrongType
+		}
+		set = func(value reflect.Value) error {
+			outgoing.SetString(value.String())
+			return nil
+		}
+	case `Date`, `DateTime`, `Time`:
+		if valueType != timeType {
+			return nil, wrongType
+		}
+		set = func(value reflect.Value) error {
+			outgoing.SetDateTime(value.Interface().(time.Time))
+			return nil
+		}
+	case `Blob`, `SpatialObj`:
+		if valueType != bytesType {
+			return nil, wrongType
+		}
+		set = func(value reflect.Value) error {
+			if value.IsNil() {
+				outgoing.SetNull()
+				return nil
+			}
+			outgoing.SetBlob(value.Bytes())
+			return nil
+		}
+	default:
+		return nil, wrongType
+	}
+
+	index := field.index
+	return func(structValue reflect.Value) error {
+		value := structValue.FieldByIndex(index)
+		if isPtr {
+			if value.IsNil() {
+				outgoing.SetNull()
+				return nil
+			}
+			value = value.Elem()
+		}
+		return set(value)
+	}, nil
+}
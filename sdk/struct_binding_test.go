@@ -0,0 +1,198 @@
+package sdk_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type boundRow struct {
+	Flag     *bool       `ayx:"Field1"`
+	Small    int         `ayx:"Field3"`
+	Big      *int64      `ayx:"Field5"`
+	Amount   sdk.Decimal `ayx:"Field8"`
+	Text     string      `ayx:"Field9"`
+	Date     *time.Time  `ayx:"Field13"`
+	Ignored  string      `ayx:"-"`
+	internal string
+}
+
+type outputRow struct {
+	Flag   *bool       `ayx:"Flag"`
+	Small  int16       `ayx:"Small"`
+	Big    *int64      `ayx:"Big"`
+	Amount sdk.Decimal `ayx:"Amount,size=19,scale=2"`
+	Text   string      `ayx:"Text,type=V_String,size=100"`
+	Date   *time.Time  `ayx:"Date,type=Date"`
+}
+
+type StructBindingTool struct {
+	rows   []boundRow
+	output sdk.OutputAnchor
+}
+
+func (s *StructBindingTool) Init(provider sdk.Provider) {
+	s.output = provider.GetOutputAnchor(`Output`)
+}
+
+func (s *StructBindingTool) OnInputConnectionOpened(_ sdk.InputConnection) {
+	info, err := sdk.NewOutgoingRecordInfoFromStruct(outputRow{}, `StructBindingTool`)
+	if err != nil {
+		panic(err.Error())
+	}
+	s.output.Open(info)
+}
+
+func (s *StructBindingTool) OnRecordPacket(connection sdk.InputConnection) {
+	row := boundRow{}
+	binder, err := connection.Metadata().Bind(&row)
+	if err != nil {
+		panic(err.Error())
+	}
+	var rows []boundRow
+	err = binder.ScanAll(connection.Read(), &rows)
+	if err != nil {
+		panic(err.Error())
+	}
+	for _, row := range rows {
+		s.rows = append(s.rows, row)
+		err = s.output.WriteStruct(outputRow{
+			Flag:   row.Flag,
+			Small:  int16(row.Small),
+			Big:    row.Big,
+			Amount: row.Amount,
+			Text:   row.Text,
+			Date:   row.Date,
+		})
+		if err != nil {
+			panic(err.Error())
+		}
+	}
+}
+
+func (s *StructBindingTool) OnComplete() {}
+
+func TestStructBinding(t *testing.T) {
+	implementation := &StructBindingTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if len(implementation.rows) != 4 {
+		t.Fatalf(`expected 4 rows but got %v`, len(implementation.rows))
+	}
+	first := implementation.rows[0]
+	if first.Flag == nil || *first.Flag != true {
+		t.Fatalf(`expected true but got %v`, first.Flag)
+	}
+	if first.Small != 100 {
+		t.Fatalf(`expected 100 but got %v`, first.Small)
+	}
+	if first.Big == nil || *first.Big != 10000 {
+		t.Fatalf(`expected 10000 but got %v`, first.Big)
+	}
+	if first.Amount.String() != `234.56` {
+		t.Fatalf(`expected 234.56 but got %v`, first.Amount.String())
+	}
+	if first.Text != `ABC` {
+		t.Fatalf(`expected ABC but got %v`, first.Text)
+	}
+	if first.Date == nil || !first.Date.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf(`expected 2020-01-01 but got %v`, first.Date)
+	}
+	third := implementation.rows[2]
+	if third.Flag != nil || third.Big != nil || third.Date != nil {
+		t.Fatalf(`expected nil pointers but got %v, %v, and %v`, third.Flag, third.Big, third.Date)
+	}
+	if third.Small != 0 || third.Text != `` || !third.Amount.IsZero() {
+		t.Fatalf(`expected zero values but got %v, '%v', and %v`, third.Small, third.Text, third.Amount)
+	}
+
+	if expectedValues := []interface{}{true, false, nil, true}; !reflect.DeepEqual(expectedValues, collector.Data[`Flag`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Flag`])
+	}
+	if expectedValues := []interface{}{100, -100, 0, -110}; !reflect.DeepEqual(expectedValues, collector.Data[`Small`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Small`])
+	}
+	if expectedValues := []interface{}{10000, -10000, nil, 2340}; !reflect.DeepEqual(expectedValues, collector.Data[`Big`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Big`])
+	}
+	if expectedValues := []interface{}{`234.56`, `-234.56`, `0.00`, `98.20`}; !reflect.DeepEqual(expectedValues, decimalStrings(collector.Data[`Amount`])) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Amount`])
+	}
+	if expectedValues := []interface{}{`ABC`, `DE|"FG`, ``, ``}; !reflect.DeepEqual(expectedValues, collector.Data[`Text`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Text`])
+	}
+	if expectedValues := []interface{}{time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 2, 3, 0, 0, 0, 0, time.UTC), nil, time.Date(2020, 2, 13, 0, 0, 0, 0, time.UTC)}; !reflect.DeepEqual(expectedValues, collector.Data[`Date`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Date`])
+	}
+}
+
+func TestOutgoingRecordInfoFromStructDefaults(t *testing.T) {
+	type row struct {
+		Bool     bool
+		Byte     uint8
+		Int16    int16
+		Int32    int32
+		Int64    int
+		Float    float32
+		Double   float64
+		String   string
+		DateTime time.Time
+		Blob     []byte
+	}
+	info, err := sdk.NewOutgoingRecordInfoFromStruct(&row{}, `source`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if fixedSize := info.FixedSize(); fixedSize != 62 {
+		t.Fatalf(`expected a fixed size of 62 but got %v`, fixedSize)
+	}
+	if _, ok := info.BoolFields[`Bool`]; !ok {
+		t.Fatalf(`expected Bool to be a bool field`)
+	}
+	for _, name := range []string{`Byte`, `Int16`, `Int32`, `Int64`} {
+		if _, ok := info.IntFields[name]; !ok {
+			t.Fatalf(`expected %v to be an int field`, name)
+		}
+	}
+	for _, name := range []string{`Float`, `Double`} {
+		if _, ok := info.FloatFields[name]; !ok {
+			t.Fatalf(`expected %v to be a float field`, name)
+		}
+	}
+	if _, ok := info.StringFields[`String`]; !ok {
+		t.Fatalf(`expected String to be a string field`)
+	}
+	if _, ok := info.DateTimeFields[`DateTime`]; !ok {
+		t.Fatalf(`expected DateTime to be a date/time field`)
+	}
+	if _, ok := info.BlobFields[`Blob`]; !ok {
+		t.Fatalf(`expected Blob to be a blob field`)
+	}
+}
+
+func TestOutgoingRecordInfoFromStructErrors(t *testing.T) {
+	type missingDecimalSize struct {
+		Amount sdk.Decimal
+	}
+	_, err := sdk.NewOutgoingRecordInfoFromStruct(missingDecimalSize{}, `source`)
+	if err == nil {
+		t.Fatalf(`expected an error for a FixedDecimal without a size but got none`)
+	}
+
+	type unsupported struct {
+		Values []int
+	}
+	_, err = sdk.NewOutgoingRecordInfoFromStruct(unsupported{}, `source`)
+	if err == nil {
+		t.Fatalf(`expected an error for an unsupported field type but got none`)
+	}
+
+	_, err = sdk.NewOutgoingRecordInfoFromStruct(1, `source`)
+	if err == nil {
+		t.Fatalf(`expected an error for a non-struct value but got none`)
+	}
+}
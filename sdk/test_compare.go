@@ -0,0 +1,281 @@
+package sdk
+
+import (
+	"bytes"
+	"fmt"
+	b "github.com/tlarsendataguy/goalteryx/sdk/field_base"
+	"github.com/tlarsendataguy/goalteryx/sdk/import_file"
+	"math"
+	"strings"
+	"time"
+)
+
+const maxCompareDiffs = 20
+
+type CompareOption func(*compareOptions)
+
+type compareOptions struct {
+	ignoreOrder    bool
+	floatTolerance float64
+	checkSchema    bool
+}
+
+func IgnoreOrder() CompareOption {
+	return func(options *compareOptions) {
+		options.ignoreOrder = true
+	}
+}
+
+func FloatTolerance(tolerance float64) CompareOption {
+	return func(options *compareOptions) {
+		options.floatTolerance = tolerance
+	}
+}
+
+func CheckSchema() CompareOption {
+	return func(options *compareOptions) {
+		options.checkSchema = true
+	}
+}
+
+func (r *RecordCollector) CompareToFile(dataFile string, options ...CompareOption) error {
+	return r.compare(func() ([]b.FieldBase, []import_file.FileData) {
+		return loadDelimitedFile(dataFile)
+	}, options)
+}
+
+func (r *RecordCollector) CompareToCsv(csvFile string, options ...CompareOption) error {
+	return r.compare(func() ([]b.FieldBase, []import_file.FileData) {
+		return loadCsvFile(csvFile, r.Config.Fields())
+	}, options)
+}
+
+func (r *RecordCollector) CompareToData(fields []NewOutgoingField, rows [][]interface{}, options ...CompareOption) error {
+	return r.compare(func() ([]b.FieldBase, []import_file.FileData) {
+		return loadInputData(fieldBases(fields), rows)
+	}, options)
+}
+
+func (r *RecordCollector) CompareSchema(fields ...NewOutgoingField) error {
+	if r.Data == nil {
+		return fmt.Errorf(`no connection was opened on the collector`)
+	}
+	diffs := compareSchemas(fieldBases(fields), r.Config.Fields(), true)
+	if len(diffs) > 0 {
+		return fmt.Errorf("schema did not match:\n  %v", strings.Join(diffs, "\n  "))
+	}
+	return nil
+}
+
+func (r *RecordCollector) compare(load func() ([]b.FieldBase, []import_file.FileData), options []CompareOption) (err error) {
+	if r.Data == nil {
+		return fmt.Errorf(`no connection was opened on the collector`)
+	}
+	settings := &compareOptions{}
+	for _, option := range options {
+		option(settings)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf(`error loading expected data: %v`, recovered)
+		}
+	}()
+	expectedFields, expectedData := load()
+
+	actualFields := r.Config.Fields()
+	diffs := compareSchemas(expectedFields, actualFields, settings.checkSchema)
+	if len(diffs) > 0 {
+		return fmt.Errorf("output did not match expected data:\n  %v", strings.Join(diffs, "\n  "))
+	}
+
+	names := make([]string, len(actualFields))
+	for index, field := range actualFields {
+		names[index] = field.Name
+	}
+	expected := make([][]interface{}, len(expectedData))
+	for rowIndex, data := range expectedData {
+		row := make([]interface{}, len(names))
+		for index, name := range names {
+			row[index] = fileDataValue(data, name)
+			if value, ok := row[index].(float64); ok && actualFields[index].Type == `Float` {
+				row[index] = float64(float32(value))
+			}
+		}
+		expected[rowIndex] = row
+	}
+	actual := make([][]interface{}, 0)
+	if len(names) > 0 {
+		actual = make([][]interface{}, len(r.Data[names[0]]))
+		for rowIndex := range actual {
+			row := make([]interface{}, len(names))
+			for index, name := range names {
+				row[index] = r.Data[name][rowIndex]
+			}
+			actual[rowIndex] = row
+		}
+	}
+
+	if settings.ignoreOrder {
+		diffs = compareUnorderedRows(expected, actual, settings.floatTolerance)
+	} else {
+		diffs = compareOrderedRows(names, expected, actual, settings.floatTolerance)
+	}
+	if len(diffs) > 0 {
+		if len(diffs) > maxCompareDiffs {
+			diffs = append(diffs[:maxCompareDiffs], fmt.Sprintf(`...and %v more differences`, len(diffs)-maxCompareDiffs))
+		}
+		return fmt.Errorf("output did not match expected data (%v):\n  %v", strings.Join(names, `|`), strings.Join(diffs, "\n  "))
+	}
+	return nil
+}
+
+func compareSchemas(expected []b.FieldBase, actual []b.FieldBase, checkTypes bool) []string {
+	expectedNames := make([]string, len(expected))
+	for index, field := range expected {
+		expectedNames[index] = field.Name
+	}
+	actualNames := make([]string, len(actual))
+	for index, field := range actual {
+		actualNames[index] = field.Name
+	}
+	if strings.Join(expectedNames, "\000") != strings.Join(actualNames, "\000") {
+		return []string{fmt.Sprintf(`expected fields [%v] but got [%v]`, strings.Join(expectedNames, `, `), strings.Join(actualNames, `, `))}
+	}
+	if !checkTypes {
+		return nil
+	}
+
+	var diffs []string
+	for index, expectedField := range expected {
+		actualField := actual[index]
+		if expectedField.Type != actualField.Type {
+			diffs = append(diffs, fmt.Sprintf(`field '%v': expected type %v but got %v`, expectedField.Name, expectedField.Type, actualField.Type))
+			continue
+		}
+		switch expectedField.Type {
+		case `FixedDecimal`:
+			if expectedField.Size != actualField.Size || expectedField.Scale != actualField.Scale {
+				diffs = append(diffs, fmt.Sprintf(`field '%v': expected size %v and scale %v but got size %v and scale %v`, expectedField.Name, expectedField.Size, expectedField.Scale, actualField.Size, actualField.Scale))
+			}
+		case `String`, `WString`, `V_String`, `V_WString`, `Blob`, `SpatialObj`:
+			if expectedField.Size != actualField.Size {
+				diffs = append(diffs, fmt.Sprintf(`field '%v': expected size %v but got %v`, expectedField.Name, expectedField.Size, actualField.Size))
+			}
+		}
+	}
+	return diffs
+}
+
+func compareOrderedRows(names []string, expected [][]interface{}, actual [][]interface{}, tolerance float64) []string {
+	var diffs []string
+	for rowIndex := 0; rowIndex < len(expected) && rowIndex < len(actual); rowIndex++ {
+		for index, name := range names {
+			if !compareValuesEqual(expected[rowIndex][index], actual[rowIndex][index], tolerance) {
+				diffs = append(diffs, fmt.Sprintf(`row %v, field '%v': expected %v but got %v`, rowIndex+1, name, formatCompareValue(expected[rowIndex][index]), formatCompareValue(actual[rowIndex][index])))
+			}
+		}
+	}
+	for rowIndex := len(actual); rowIndex < len(expected); rowIndex++ {
+		diffs = append(diffs, fmt.Sprintf(`row %v missing: %v`, rowIndex+1, formatCompareRow(expected[rowIndex])))
+	}
+	for rowIndex := len(expected); rowIndex < len(actual); rowIndex++ {
+		diffs = append(diffs, fmt.Sprintf(`row %v unexpected: %v`, rowIndex+1, formatCompareRow(actual[rowIndex])))
+	}
+	return diffs
+}
+
+func compareUnorderedRows(expected [][]interface{}, actual [][]interface{}, tolerance float64) []string {
+	matched := make([]bool, len(actual))
+	var diffs []string
+	for rowIndex, expectedRow := range expected {
+		found := false
+		for actualIndex, actualRow := range actual {
+			if matched[actualIndex] || !compareRowsEqual(expectedRow, actualRow, tolerance) {
+				continue
+			}
+			matched[actualIndex] = true
+			found = true
+			break
+		}
+		if !found {
+			diffs = append(diffs, fmt.Sprintf(`expected row %v missing: %v`, rowIndex+1, formatCompareRow(expectedRow)))
+		}
+	}
+	for actualIndex, actualRow := range actual {
+		if !matched[actualIndex] {
+			diffs = append(diffs, fmt.Sprintf(`actual row %v unexpected: %v`, actualIndex+1, formatCompareRow(actualRow)))
+		}
+	}
+	return diffs
+}
+
+func compareRowsEqual(expected []interface{}, actual []interface{}, tolerance float64) bool {
+	for index := range expected {
+		if !compareValuesEqual(expected[index], actual[index], tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+func compareValuesEqual(expected interface{}, actual interface{}, tolerance float64) bool {
+	if expected == nil || actual == nil {
+		return expected == nil && actual == nil
+	}
+	if expectedDecimal, ok := expected.(Decimal); ok {
+		if actualDecimal, ok := actual.(Decimal); ok {
+			difference := expectedDecimal.Sub(actualDecimal)
+			return difference.IsZero() || math.Abs(difference.Float64()) <= tolerance
+		}
+	}
+	expectedFloat, expectedIsNumber := compareNumber(expected)
+	actualFloat, actualIsNumber := compareNumber(actual)
+	if expectedIsNumber && actualIsNumber {
+		return math.Abs(expectedFloat-actualFloat) <= tolerance
+	}
+	switch value := expected.(type) {
+	case time.Time:
+		other, ok := actual.(time.Time)
+		return ok && value.Equal(other)
+	case []byte:
+		other, ok := actual.([]byte)
+		return ok && bytes.Equal(value, other)
+	default:
+		return expected == actual
+	}
+}
+
+func compareNumber(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), true
+	case float64:
+		return number, true
+	case Decimal:
+		return number.Float64(), true
+	default:
+		return 0, false
+	}
+}
+
+func formatCompareRow(row []interface{}) string {
+	values := make([]string, len(row))
+	for index, value := range row {
+		values[index] = formatCompareValue(value)
+	}
+	return strings.Join(values, `|`)
+}
+
+func formatCompareValue(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return `<null>`
+	case string:
+		return fmt.Sprintf(`%q`, typed)
+	case time.Time:
+		return typed.Format(dateTimeFormat)
+	default:
+		return fmt.Sprintf(`%v`, typed)
+	}
+}
@@ -0,0 +1,152 @@
+package sdk_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"strings"
+	"testing"
+)
+
+func TestCompareToFile(t *testing.T) {
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	err := collector.CompareToFile(`sdk_test_passthrough_simulation.txt`, sdk.CheckSchema())
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+}
+
+func TestCompareToData(t *testing.T) {
+	runner := sdk.RegisterToolTest(&PassThroughTool{}, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectCsvInput(`Input`, `sdk_test_inputs.csv`,
+		sdk.NewInt32Field(`Id`, `source`),
+		sdk.NewV_WStringField(`Name`, `source`, 100),
+		sdk.NewDoubleField(`Amount`, `source`),
+	)
+	runner.SimulateLifecycle()
+
+	fields := []sdk.NewOutgoingField{
+		sdk.NewInt32Field(`Id`, `source`),
+		sdk.NewV_WStringField(`Name`, `source`, 100),
+		sdk.NewDoubleField(`Amount`, `source`),
+	}
+	err := collector.CompareToData(fields, [][]interface{}{
+		{1, `Alpha`, 1.5},
+		{2, `Beta, Inc`, nil},
+		{3, nil, -2.25},
+	}, sdk.CheckSchema())
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	err = collector.CompareToData(fields, [][]interface{}{
+		{3, nil, -2.2500001},
+		{1, `Alpha`, 1.5},
+		{2, `Beta, Inc`, nil},
+	}, sdk.IgnoreOrder(), sdk.FloatTolerance(0.001))
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	err = collector.CompareToData(fields, [][]interface{}{
+		{1, `Alpha`, 1.5},
+		{2, `Beta`, nil},
+	})
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+	message := err.Error()
+	if !strings.Contains(message, `row 2, field 'Name': expected "Beta" but got "Beta, Inc"`) {
+		t.Fatalf(`expected a value diff but got: %v`, message)
+	}
+	if !strings.Contains(message, `row 3 unexpected: 3|<null>|-2.25`) {
+		t.Fatalf(`expected an unexpected row but got: %v`, message)
+	}
+
+	err = collector.CompareToData(fields, [][]interface{}{
+		{1, `Alpha`, 1.5},
+		{2, `Beta, Inc`, nil},
+		{4, nil, -2.25},
+	}, sdk.IgnoreOrder())
+	if err == nil || !strings.Contains(err.Error(), `expected row 3 missing: 4|<null>|-2.25`) || !strings.Contains(err.Error(), `actual row 3 unexpected: 3|<null>|-2.25`) {
+		t.Fatalf(`expected an unordered diff but got: %v`, err)
+	}
+}
+
+func TestCompareWideDecimals(t *testing.T) {
+	runner := sdk.RegisterToolTest(&PassThroughTool{}, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	fields := []sdk.NewOutgoingField{sdk.NewFixedDecimalField(`Amount`, `source`, 19, 2)}
+	runner.ConnectInputData(`Input`, fields, [][]interface{}{{`1234567890123456.78`}})
+	runner.SimulateLifecycle()
+
+	err := collector.CompareToData(fields, [][]interface{}{{`1234567890123456.78`}})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	err = collector.CompareToData(fields, [][]interface{}{{`1234567890123456.77`}})
+	if err == nil || !strings.Contains(err.Error(), `row 1, field 'Amount': expected 1234567890123456.77 but got 1234567890123456.78`) {
+		t.Fatalf(`expected a decimal diff but got: %v`, err)
+	}
+
+	err = collector.CompareToData(fields, [][]interface{}{{`1234567890123456.77`}}, sdk.FloatTolerance(0.01))
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+}
+
+func TestCompareSchema(t *testing.T) {
+	runner := sdk.RegisterToolTest(&PassThroughTool{}, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectCsvInput(`Input`, `sdk_test_inputs.csv`,
+		sdk.NewInt32Field(`Id`, `source`),
+		sdk.NewV_WStringField(`Name`, `source`, 100),
+	)
+	runner.SimulateLifecycle()
+
+	err := collector.CompareSchema(sdk.NewInt32Field(`Id`, ``), sdk.NewV_WStringField(`Name`, ``, 100))
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	err = collector.CompareSchema(sdk.NewInt64Field(`Id`, ``), sdk.NewV_WStringField(`Name`, ``, 50))
+	if err == nil || !strings.Contains(err.Error(), `field 'Id': expected type Int64 but got Int32`) || !strings.Contains(err.Error(), `field 'Name': expected size 50 but got 100`) {
+		t.Fatalf(`expected a schema diff but got: %v`, err)
+	}
+	err = collector.CompareSchema(sdk.NewInt32Field(`Id`, ``))
+	if err == nil || !strings.Contains(err.Error(), `expected fields [Id] but got [Id, Name]`) {
+		t.Fatalf(`expected a field list diff but got: %v`, err)
+	}
+}
+
+func TestCompareToCsv(t *testing.T) {
+	runner := sdk.RegisterToolTest(&PassThroughTool{}, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInputData(`Input`, []sdk.NewOutgoingField{
+		sdk.NewInt64Field(`Id`, `source`),
+		sdk.NewV_StringField(`Name`, `source`, 100),
+		sdk.NewFixedDecimalField(`Amount`, `source`, 19, 2),
+		sdk.NewDateField(`Date`, `source`),
+	}, [][]interface{}{
+		{1, `Alpha`, 1.5, `2020-01-02`},
+		{2, `Beta, Inc`, nil, `2020-03-04`},
+		{3, nil, -2.25, nil},
+	})
+	runner.SimulateLifecycle()
+
+	err := collector.CompareToCsv(`sdk_test_inputs.csv`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+}
+
+func TestCompareWithoutConnection(t *testing.T) {
+	collector := &sdk.RecordCollector{}
+	if err := collector.CompareToFile(`sdk_test_passthrough_simulation.txt`); err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
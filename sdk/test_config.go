@@ -0,0 +1,33 @@
+package sdk
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+func VerifyConfigMigration(migrator ConfigMigrator, configFile string, expectedFile string) error {
+	configXml, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf(`error reading config file: %v`, err.Error())
+	}
+	expectedXml, err := ioutil.ReadFile(expectedFile)
+	if err != nil {
+		return fmt.Errorf(`error reading expected config file: %v`, err.Error())
+	}
+	migrated, _, err := migrator.Migrate(string(configXml))
+	if err != nil {
+		return err
+	}
+	actual, err := parseConfigXml(migrated)
+	if err != nil {
+		return err
+	}
+	expected, err := parseConfigXml(string(expectedXml))
+	if err != nil {
+		return fmt.Errorf(`error reading expected config file: %v`, err.Error())
+	}
+	if actual.String() != expected.String() {
+		return fmt.Errorf("migrated config did not match expected config:\n  expected %v\n  but got  %v", expected.String(), actual.String())
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package sdk
+
+import "unsafe"
+
+type testEngine struct {
+	handle           unsafe.Pointer
+	noCache          bool
+	reservations     uint32
+	browseEverywhere map[string]*RecordCollector
+	harnesses        []*goPluginSharedMemory
+	log              *testMessageLog
+}
+
+var testEngines = map[unsafe.Pointer]*testEngine{}
+
+func newTestEngine(noCache bool, log *testMessageLog) *testEngine {
+	engine := &testEngine{
+		handle:           generateTestEngine(),
+		noCache:          noCache,
+		browseEverywhere: make(map[string]*RecordCollector),
+		log:              log,
+	}
+	testEngines[engine.handle] = engine
+	return engine
+}
+
+func (e *testEngine) reserveBrowseEverywhere() uint32 {
+	e.reservations++
+	return e.reservations
+}
+
+func (e *testEngine) getBrowseEverywhereIi(name string) unsafe.Pointer {
+	collector, ok := e.browseEverywhere[name]
+	if !ok {
+		return nil
+	}
+	sharedMemory := registerTestHarness(collector, e.noCache)
+	e.harnesses = append(e.harnesses, sharedMemory)
+	ii := generateIncomingConnectionInterface()
+	if e.noCache {
+		callPiAddIncomingConnectionNoCache(sharedMemory, name, ``, ii)
+	} else {
+		callPiAddIncomingConnection(sharedMemory, name, ``, ii)
+	}
+	return ii
+}
@@ -0,0 +1,395 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	b "github.com/tlarsendataguy/goalteryx/sdk/field_base"
+	"github.com/tlarsendataguy/goalteryx/sdk/import_file"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const inputSource = `FilePusher`
+
+func (r *FileTestRunner) ConnectInputData(name string, fields []NewOutgoingField, rows [][]interface{}) {
+	r.connectPusher(name, func() ([]b.FieldBase, []import_file.FileData) {
+		return loadInputData(fieldBases(fields), rows)
+	})
+}
+
+func (r *FileTestRunner) ConnectInputStructs(name string, rows interface{}) {
+	r.connectPusher(name, func() ([]b.FieldBase, []import_file.FileData) {
+		return loadInputStructs(rows)
+	})
+}
+
+func (r *FileTestRunner) ConnectCsvInput(name string, csvFile string, fields ...NewOutgoingField) {
+	r.connectPusher(name, func() ([]b.FieldBase, []import_file.FileData) {
+		return loadCsvFile(csvFile, fieldBases(fields))
+	})
+}
+
+func (r *FileTestRunner) ConnectJsonInput(name string, jsonFile string, fields ...NewOutgoingField) {
+	r.connectPusher(name, func() ([]b.FieldBase, []import_file.FileData) {
+		return loadJsonFile(jsonFile, fieldBases(fields))
+	})
+}
+
+func fieldBases(fields []NewOutgoingField) []b.FieldBase {
+	bases := make([]b.FieldBase, len(fields))
+	for index, newField := range fields {
+		field := newField()
+		bases[index] = b.FieldBase{
+			Name:   field.Name,
+			Type:   field.Type,
+			Source: field.Source,
+			Size:   field.Size,
+			Scale:  field.Scale,
+		}
+	}
+	return bases
+}
+
+func loadInputData(fields []b.FieldBase, rows [][]interface{}) ([]b.FieldBase, []import_file.FileData) {
+	data := make([]import_file.FileData, len(rows))
+	for rowIndex, row := range rows {
+		if len(row) != len(fields) {
+			panic(fmt.Sprintf(`row %v has %v values but %v fields were defined`, rowIndex, len(row), len(fields)))
+		}
+		data[rowIndex] = inputRowToFileData(fields, row)
+	}
+	return fields, data
+}
+
+func loadInputStructs(rows interface{}) ([]b.FieldBase, []import_file.FileData) {
+	slice := reflect.ValueOf(rows)
+	if slice.Kind() != reflect.Slice {
+		panic(fmt.Sprintf(`expected a slice of structs but got %v`, reflect.TypeOf(rows)))
+	}
+	elemType := slice.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	info, err := NewOutgoingRecordInfoFromStruct(reflect.New(elemType).Interface(), inputSource)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	fields := make([]b.FieldBase, len(info.outgoingFields))
+	for index, field := range info.outgoingFields {
+		fields[index] = b.FieldBase{Name: field.Name, Type: field.Type, Source: field.Source, Size: field.Size, Scale: field.Scale}
+	}
+
+	data := make([]import_file.FileData, slice.Len())
+	for rowIndex := 0; rowIndex < slice.Len(); rowIndex++ {
+		err = info.SetFromStruct(slice.Index(rowIndex).Interface())
+		if err != nil {
+			panic(fmt.Sprintf(`error reading struct at index %v: %v`, rowIndex, err.Error()))
+		}
+		row := make([]interface{}, len(info.outgoingFields))
+		for index, field := range info.outgoingFields {
+			row[index] = currentFieldValue(field)
+		}
+		data[rowIndex] = inputRowToFileData(fields, row)
+	}
+	return fields, data
+}
+
+func currentFieldValue(field *outgoingField) interface{} {
+	var value interface{}
+	var isNull bool
+	switch field.Type {
+	case `Bool`:
+		value, isNull = field.GetCurrentBool()
+	case `Byte`, `Int16`, `Int32`, `Int64`:
+		value, isNull = field.GetCurrentInt()
+	case `Float`, `Double`:
+		value, isNull = field.GetCurrentFloat()
+	case `FixedDecimal`:
+		value, isNull = field.GetCurrentDecimal()
+	case `String`, `WString`, `V_String`, `V_WString`:
+		value, isNull = field.GetCurrentString()
+	case `Date`, `DateTime`, `Time`:
+		value, isNull = field.GetCurrentDateTime()
+	case `Blob`, `SpatialObj`:
+		value, isNull = field.GetCurrentBlob()
+	}
+	if isNull {
+		return nil
+	}
+	return value
+}
+
+func loadCsvFile(csvFile string, fields []b.FieldBase) ([]b.FieldBase, []import_file.FileData) {
+	file, err := os.Open(csvFile)
+	if err != nil {
+		panic(fmt.Sprintf(`error opening csv file: %v`, err.Error()))
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf(`error reading csv file: %v`, err.Error()))
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	if len(fields) == 0 {
+		fields = make([]b.FieldBase, len(header))
+		for index, name := range header {
+			fields[index] = b.FieldBase{Name: name, Type: `V_WString`, Source: inputSource, Size: 1073741823}
+		}
+	}
+	columns := make([]int, len(fields))
+	for index, field := range fields {
+		columns[index] = indexOfColumn(header, field.Name)
+		if columns[index] == -1 {
+			panic(fmt.Sprintf(`field '%v' does not exist in the csv header`, field.Name))
+		}
+	}
+
+	data := make([]import_file.FileData, len(records)-1)
+	for rowIndex, record := range records[1:] {
+		row := make([]interface{}, len(fields))
+		for index, column := range columns {
+			if record[column] != `` {
+				row[index] = record[column]
+			}
+		}
+		data[rowIndex] = inputRowToFileData(fields, row)
+	}
+	return fields, data
+}
+
+func indexOfColumn(header []string, name string) int {
+	for index, column := range header {
+		if column == name {
+			return index
+		}
+	}
+	return -1
+}
+
+func loadJsonFile(jsonFile string, fields []b.FieldBase) ([]b.FieldBase, []import_file.FileData) {
+	content, err := ioutil.ReadFile(jsonFile)
+	if err != nil {
+		panic(fmt.Sprintf(`error opening json file: %v`, err.Error()))
+	}
+
+	var rawObjects []json.RawMessage
+	err = json.Unmarshal(content, &rawObjects)
+	if err != nil {
+		panic(fmt.Sprintf(`error reading json file, expected an array of objects: %v`, err.Error()))
+	}
+	objects := make([]map[string]interface{}, len(rawObjects))
+	for index, rawObject := range rawObjects {
+		objects[index] = decodeJsonObject(rawObject)
+	}
+
+	if len(fields) == 0 {
+		if len(objects) == 0 {
+			return nil, nil
+		}
+		fields = inferJsonFields(rawObjects[0], objects[0])
+	}
+
+	data := make([]import_file.FileData, len(objects))
+	for rowIndex, object := range objects {
+		row := make([]interface{}, len(fields))
+		for index, field := range fields {
+			row[index] = object[field.Name]
+		}
+		data[rowIndex] = inputRowToFileData(fields, row)
+	}
+	return fields, data
+}
+
+func decodeJsonObject(rawObject json.RawMessage) map[string]interface{} {
+	var object map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(rawObject))
+	decoder.UseNumber()
+	err := decoder.Decode(&object)
+	if err != nil {
+		panic(fmt.Sprintf(`error reading json object: %v`, err.Error()))
+	}
+	return object
+}
+
+func inferJsonFields(rawObject json.RawMessage, object map[string]interface{}) []b.FieldBase {
+	decoder := json.NewDecoder(bytes.NewReader(rawObject))
+	_, _ = decoder.Token()
+	var fields []b.FieldBase
+	for decoder.More() {
+		token, _ := decoder.Token()
+		name := token.(string)
+		var skip json.RawMessage
+		_ = decoder.Decode(&skip)
+
+		field := b.FieldBase{Name: name, Type: `V_WString`, Source: inputSource, Size: 1073741823}
+		switch object[name].(type) {
+		case bool:
+			field = b.FieldBase{Name: name, Type: `Bool`, Source: inputSource, Size: 1}
+		case json.Number:
+			field = b.FieldBase{Name: name, Type: `Double`, Source: inputSource, Size: 8}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func inputRowToFileData(fields []b.FieldBase, row []interface{}) import_file.FileData {
+	data := import_file.FileData{
+		BoolFields:     make(map[string]interface{}),
+		IntFields:      make(map[string]interface{}),
+		DecimalFields:  make(map[string]interface{}),
+		StringFields:   make(map[string]interface{}),
+		DateTimeFields: make(map[string]interface{}),
+		BlobFields:     make(map[string]interface{}),
+	}
+	for index, field := range fields {
+		value := normalizeInputValue(field, row[index])
+		switch field.Type {
+		case `Bool`:
+			data.BoolFields[field.Name] = value
+		case `Byte`, `Int16`, `Int32`, `Int64`:
+			data.IntFields[field.Name] = value
+		case `Float`, `Double`, `FixedDecimal`:
+			data.DecimalFields[field.Name] = value
+		case `String`, `WString`, `V_String`, `V_WString`:
+			data.StringFields[field.Name] = value
+		case `Date`, `DateTime`, `Time`:
+			data.DateTimeFields[field.Name] = value
+		case `Blob`, `SpatialObj`:
+			data.BlobFields[field.Name] = value
+		default:
+			panic(fmt.Sprintf(`'%v' is not a valid field type`, field.Type))
+		}
+	}
+	return data
+}
+
+func normalizeInputValue(field b.FieldBase, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if number, ok := value.(json.Number); ok {
+		value = number.String()
+	}
+	if field.Type == `FixedDecimal` {
+		return normalizeDecimalValue(field, value)
+	}
+	if decimal, ok := value.(Decimal); ok {
+		value = decimal.String()
+	}
+	switch field.Type {
+	case `Bool`:
+		switch typed := value.(type) {
+		case bool:
+			return typed
+		case string:
+			boolValue, err := strconv.ParseBool(typed)
+			if err == nil {
+				return boolValue
+			}
+		}
+	case `Byte`, `Int16`, `Int32`, `Int64`:
+		reflected := reflect.ValueOf(value)
+		switch {
+		case isIntKind(reflected.Kind()):
+			return int(reflectedInt(reflected))
+		case reflected.Kind() == reflect.String:
+			intValue, err := strconv.Atoi(reflected.String())
+			if err == nil {
+				return intValue
+			}
+		}
+	case `Float`, `Double`, `FixedDecimal`:
+		reflected := reflect.ValueOf(value)
+		switch {
+		case isFloatKind(reflected.Kind()):
+			return reflected.Float()
+		case isIntKind(reflected.Kind()):
+			return float64(reflectedInt(reflected))
+		case reflected.Kind() == reflect.String:
+			floatValue, err := strconv.ParseFloat(reflected.String(), 64)
+			if err == nil {
+				return floatValue
+			}
+		}
+	case `String`, `WString`, `V_String`, `V_WString`:
+		if stringValue, ok := value.(string); ok {
+			return stringValue
+		}
+		return fmt.Sprintf(`%v`, value)
+	case `Date`, `DateTime`, `Time`:
+		switch typed := value.(type) {
+		case time.Time:
+			return typed
+		case string:
+			timeValue, err := time.Parse(inputTimeFormat(field.Type), typed)
+			if err == nil {
+				return timeValue
+			}
+		}
+	case `Blob`, `SpatialObj`:
+		switch typed := value.(type) {
+		case []byte:
+			return typed
+		case string:
+			blobValue, err := base64.StdEncoding.DecodeString(typed)
+			if err == nil {
+				return blobValue
+			}
+		}
+	}
+	panic(fmt.Sprintf(`'%v' is not a valid value for %v field '%v'`, value, field.Type, field.Name))
+}
+
+func normalizeDecimalValue(field b.FieldBase, value interface{}) Decimal {
+	if decimal, ok := value.(Decimal); ok {
+		return decimal
+	}
+	reflected := reflect.ValueOf(value)
+	valueStr := ``
+	switch {
+	case isFloatKind(reflected.Kind()):
+		valueStr = strconv.FormatFloat(reflected.Float(), 'f', -1, 64)
+	case isIntKind(reflected.Kind()):
+		valueStr = strconv.FormatInt(reflectedInt(reflected), 10)
+	case reflected.Kind() == reflect.String:
+		valueStr = reflected.String()
+	}
+	decimal, err := ParseDecimal(valueStr)
+	if err != nil {
+		panic(fmt.Sprintf(`'%v' is not a valid value for %v field '%v'`, value, field.Type, field.Name))
+	}
+	return decimal
+}
+
+func reflectedInt(value reflect.Value) int64 {
+	switch value.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(value.Uint())
+	default:
+		return value.Int()
+	}
+}
+
+func inputTimeFormat(fieldType string) string {
+	switch fieldType {
+	case `Date`:
+		return dateFormat
+	case `Time`:
+		return timeFormat
+	default:
+		return dateTimeFormat
+	}
+}
@@ -0,0 +1,264 @@
+package sdk_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConnectInputData(t *testing.T) {
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInputData(`Input`, []sdk.NewOutgoingField{
+		sdk.NewInt32Field(`Id`, `source`),
+		sdk.NewV_WStringField(`Name`, `source`, 100),
+		sdk.NewFixedDecimalField(`Amount`, `source`, 19, 2),
+		sdk.NewDateField(`Date`, `source`),
+		sdk.NewBoolField(`Active`, `source`),
+	}, [][]interface{}{
+		{1, `Alpha`, 1.5, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		{int64(2), nil, sdk.NewDecimal(-225, 2), `2020-03-04`, nil},
+		{uint8(3), `Gamma`, 7, nil, false},
+	})
+	runner.SimulateLifecycle()
+
+	if expectedValues := []interface{}{1, 2, 3}; !reflect.DeepEqual(expectedValues, collector.Data[`Id`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Id`])
+	}
+	if expectedValues := []interface{}{`Alpha`, nil, `Gamma`}; !reflect.DeepEqual(expectedValues, collector.Data[`Name`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Name`])
+	}
+	if expectedValues := []interface{}{`1.50`, `-2.25`, `7.00`}; !reflect.DeepEqual(expectedValues, decimalStrings(collector.Data[`Amount`])) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Amount`])
+	}
+	if expectedValues := []interface{}{time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC), nil}; !reflect.DeepEqual(expectedValues, collector.Data[`Date`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Date`])
+	}
+	if expectedValues := []interface{}{true, nil, false}; !reflect.DeepEqual(expectedValues, collector.Data[`Active`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Active`])
+	}
+}
+
+func TestConnectInputDataInvalidValue(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf(`expected a panic but got none`)
+		}
+	}()
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInputData(`Input`, []sdk.NewOutgoingField{
+		sdk.NewInt32Field(`Id`, `source`),
+	}, [][]interface{}{{`not a number`}})
+	runner.SimulateLifecycle()
+}
+
+func TestConnectInputStructs(t *testing.T) {
+	type inputRow struct {
+		Id     int     `ayx:"Id,type=Int32"`
+		Name   *string `ayx:"Name,size=100"`
+		Amount float64 `ayx:"Amount"`
+	}
+	name := `Alpha`
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.Presort(`Input`, sdk.PresortInfo{
+		SortFields: []sdk.SortField{{Field: `Id`, Order: sdk.Descending}},
+	}))
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInputStructs(`Input`, []inputRow{
+		{Id: 1, Name: &name, Amount: 1.5},
+		{Id: 2, Name: nil, Amount: -2},
+	})
+	runner.SimulateLifecycle()
+
+	if fieldType := collector.Config.Fields()[0].Type; fieldType != `Int32` {
+		t.Fatalf(`expected Int32 but got %v`, fieldType)
+	}
+	if expectedValues := []interface{}{2, 1}; !reflect.DeepEqual(expectedValues, collector.Data[`Id`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Id`])
+	}
+	if expectedValues := []interface{}{nil, `Alpha`}; !reflect.DeepEqual(expectedValues, collector.Data[`Name`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Name`])
+	}
+	if expectedValues := []interface{}{-2.0, 1.5}; !reflect.DeepEqual(expectedValues, collector.Data[`Amount`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Amount`])
+	}
+}
+
+func TestConnectCsvInput(t *testing.T) {
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectCsvInput(`Input`, `sdk_test_inputs.csv`)
+	runner.SimulateLifecycle()
+
+	if fields := collector.Config.Fields(); len(fields) != 4 || fields[3].Type != `V_WString` {
+		t.Fatalf(`expected 4 V_WString fields but got %v`, fields)
+	}
+	if expectedValues := []interface{}{`Alpha`, `Beta, Inc`, nil}; !reflect.DeepEqual(expectedValues, collector.Data[`Name`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Name`])
+	}
+}
+
+func TestConnectCsvInputWithSchema(t *testing.T) {
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectCsvInput(`Input`, `sdk_test_inputs.csv`,
+		sdk.NewDateField(`Date`, `source`),
+		sdk.NewInt64Field(`Id`, `source`),
+		sdk.NewDoubleField(`Amount`, `source`),
+	)
+	runner.SimulateLifecycle()
+
+	if fields := collector.Config.Fields(); len(fields) != 3 || fields[0].Name != `Date` {
+		t.Fatalf(`expected the schema's 3 fields in order but got %v`, fields)
+	}
+	if expectedValues := []interface{}{1, 2, 3}; !reflect.DeepEqual(expectedValues, collector.Data[`Id`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Id`])
+	}
+	if expectedValues := []interface{}{1.5, nil, -2.25}; !reflect.DeepEqual(expectedValues, collector.Data[`Amount`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Amount`])
+	}
+	if expectedValues := []interface{}{time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC), nil}; !reflect.DeepEqual(expectedValues, collector.Data[`Date`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Date`])
+	}
+}
+
+func TestConnectJsonInput(t *testing.T) {
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectJsonInput(`Input`, `sdk_test_inputs.json`)
+	runner.SimulateLifecycle()
+
+	fields := collector.Config.Fields()
+	var types []string
+	for _, field := range fields {
+		types = append(types, field.Name+`:`+field.Type)
+	}
+	if expectedTypes := []string{`Id:Double`, `Name:V_WString`, `Active:Bool`, `Date:V_WString`}; !reflect.DeepEqual(expectedTypes, types) {
+		t.Fatalf(`expected %v but got %v`, expectedTypes, types)
+	}
+	if expectedValues := []interface{}{1.0, 2.0, 3.0}; !reflect.DeepEqual(expectedValues, collector.Data[`Id`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Id`])
+	}
+	if expectedValues := []interface{}{true, false, nil}; !reflect.DeepEqual(expectedValues, collector.Data[`Active`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Active`])
+	}
+}
+
+func TestConnectJsonInputWithSchemaNoCache(t *testing.T) {
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.NoCache(true))
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectJsonInput(`Input`, `sdk_test_inputs.json`,
+		sdk.NewInt16Field(`Id`, `source`),
+		sdk.NewV_StringField(`Name`, `source`, 100),
+		sdk.NewDateField(`Date`, `source`),
+	)
+	runner.SimulateLifecycle()
+
+	if expectedValues := []interface{}{1, 2, 3}; !reflect.DeepEqual(expectedValues, collector.Data[`Id`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Id`])
+	}
+	if expectedValues := []interface{}{`Alpha`, nil, `Gamma`}; !reflect.DeepEqual(expectedValues, collector.Data[`Name`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Name`])
+	}
+	if expectedValues := []interface{}{time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC), nil}; !reflect.DeepEqual(expectedValues, collector.Data[`Date`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Date`])
+	}
+}
+
+type DecimalCaptureTool struct {
+	amount sdk.IncomingDecimalField
+	values []interface{}
+}
+
+func (d *DecimalCaptureTool) Init(_ sdk.Provider) {}
+
+func (d *DecimalCaptureTool) OnInputConnectionOpened(connection sdk.InputConnection) {
+	d.amount, _ = connection.Metadata().GetDecimalField(`Amount`)
+}
+
+func (d *DecimalCaptureTool) OnRecordPacket(connection sdk.InputConnection) {
+	packet := connection.Read()
+	for packet.Next() {
+		value, isNull := d.amount.GetValue(packet.Record())
+		if isNull {
+			d.values = append(d.values, nil)
+			continue
+		}
+		d.values = append(d.values, value.String())
+	}
+}
+
+func (d *DecimalCaptureTool) OnComplete() {}
+
+func TestInputsKeepDecimalPrecision(t *testing.T) {
+	type decimalRow struct {
+		Amount sdk.Decimal `ayx:"Amount,size=19,scale=2"`
+	}
+	large, _ := sdk.ParseDecimal(`1234567890123456.78`)
+	dir, err := ioutil.TempDir(``, `decimals`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	csvFile := filepath.Join(dir, `decimals.csv`)
+	_ = ioutil.WriteFile(csvFile, []byte("Id,Amount\n1,1234567890123456.78\n2,-0.01\n3,\n"), 0644)
+	jsonFile := filepath.Join(dir, `decimals.json`)
+	_ = ioutil.WriteFile(jsonFile, []byte(`[{"Amount": 1234567890123456.78}, {"Amount": -0.01}, {"Amount": null}]`), 0644)
+	amountField := sdk.NewFixedDecimalField(`Amount`, `source`, 19, 2)
+	yxdbFile := filepath.Join(dir, `decimals.yxdb`)
+	yxdbInfo, _ := sdk.NewOutgoingRecordInfo([]sdk.NewOutgoingField{amountField})
+	yxdbWriter, err := sdk.CreateYxdb(yxdbFile, yxdbInfo)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	_ = yxdbInfo.DecimalFields[`Amount`].SetDecimal(large)
+	_ = yxdbWriter.Write()
+	_ = yxdbInfo.DecimalFields[`Amount`].SetDecimal(sdk.NewDecimal(-1, 2))
+	_ = yxdbWriter.Write()
+	yxdbInfo.DecimalFields[`Amount`].SetNull()
+	_ = yxdbWriter.Write()
+	_ = yxdbWriter.Close()
+
+	connectors := map[string]func(runner *sdk.FileTestRunner){
+		`data`: func(runner *sdk.FileTestRunner) {
+			runner.ConnectInputData(`Input`, []sdk.NewOutgoingField{amountField}, [][]interface{}{{large}, {`-0.01`}, {nil}})
+		},
+		`structs`: func(runner *sdk.FileTestRunner) {
+			runner.ConnectInputStructs(`Input`, []decimalRow{{Amount: large}, {Amount: sdk.NewDecimal(-1, 2)}})
+		},
+		`csv`: func(runner *sdk.FileTestRunner) {
+			runner.ConnectCsvInput(`Input`, csvFile, sdk.NewInt32Field(`Id`, `source`), amountField)
+		},
+		`json`: func(runner *sdk.FileTestRunner) {
+			runner.ConnectJsonInput(`Input`, jsonFile, amountField)
+		},
+		`yxdb`: func(runner *sdk.FileTestRunner) {
+			runner.ConnectInput(`Input`, yxdbFile)
+		},
+	}
+	for name, connect := range connectors {
+		implementation := &DecimalCaptureTool{}
+		runner := sdk.RegisterToolTest(implementation, 1, ``)
+		connect(runner)
+		runner.SimulateLifecycle()
+
+		expected := []interface{}{`1234567890123456.78`, `-0.01`, nil}
+		if name == `structs` {
+			expected = expected[:2]
+		}
+		if !reflect.DeepEqual(expected, implementation.values) {
+			t.Fatalf(`%v: expected %v but got %v`, name, expected, implementation.values)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package sdk
+
+import "sync"
+
+type TestMessage struct {
+	Status  MessageStatus
+	Message string
+}
+
+type testMessageLog struct {
+	mutex    sync.Mutex
+	messages []TestMessage
+	progress []float64
+	configs  []string
+}
+
+func (l *testMessageLog) addMessage(status MessageStatus, message string) {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	l.messages = append(l.messages, TestMessage{Status: status, Message: message})
+	l.mutex.Unlock()
+}
+
+func (l *testMessageLog) addProgress(progress float64) {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	l.progress = append(l.progress, progress)
+	l.mutex.Unlock()
+}
+
+func (l *testMessageLog) addConfig(config string) {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	l.configs = append(l.configs, config)
+	l.mutex.Unlock()
+}
+
+func (r *FileTestRunner) Messages(status MessageStatus) []string {
+	r.messages.mutex.Lock()
+	defer r.messages.mutex.Unlock()
+	messages := []string{}
+	for _, message := range r.messages.messages {
+		if message.Status == status {
+			messages = append(messages, message.Message)
+		}
+	}
+	return messages
+}
+
+func (r *FileTestRunner) AllMessages() []TestMessage {
+	r.messages.mutex.Lock()
+	defer r.messages.mutex.Unlock()
+	return append([]TestMessage{}, r.messages.messages...)
+}
+
+func (r *FileTestRunner) ProgressUpdates() []float64 {
+	r.messages.mutex.Lock()
+	defer r.messages.mutex.Unlock()
+	return append([]float64{}, r.messages.progress...)
+}
+
+func (r *FileTestRunner) ToolConfigUpdates() []string {
+	r.messages.mutex.Lock()
+	defer r.messages.mutex.Unlock()
+	return append([]string{}, r.messages.configs...)
+}
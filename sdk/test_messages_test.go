@@ -0,0 +1,67 @@
+package sdk_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type messageTester struct {
+	PassThroughTool
+	provider sdk.Provider
+}
+
+func (m *messageTester) Init(provider sdk.Provider) {
+	m.PassThroughTool.Init(provider)
+	m.provider = provider
+	provider.Io().Info(`starting`)
+	provider.Io().NotifyFileInput(`input.txt`)
+	provider.Environment().UpdateToolConfig(`<Configuration><Updated /></Configuration>`)
+}
+
+func (m *messageTester) OnComplete() {
+	m.provider.Io().Warn(`almost done`)
+	m.provider.Io().UpdateProgress(0.5)
+	m.provider.Io().Error(`something went wrong`)
+	m.provider.Io().UpdateProgress(1.0)
+}
+
+func TestCaptureMessages(t *testing.T) {
+	implementation := &messageTester{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if expected := []string{`something went wrong`}; !reflect.DeepEqual(expected, runner.Messages(sdk.Error)) {
+		t.Fatalf(`expected %v but got %v`, expected, runner.Messages(sdk.Error))
+	}
+	if expected := []string{`almost done`}; !reflect.DeepEqual(expected, runner.Messages(sdk.Warning)) {
+		t.Fatalf(`expected %v but got %v`, expected, runner.Messages(sdk.Warning))
+	}
+	if expected := []string{`starting`}; !reflect.DeepEqual(expected, runner.Messages(sdk.Info)) {
+		t.Fatalf(`expected %v but got %v`, expected, runner.Messages(sdk.Info))
+	}
+	if expected := []string{`input.txt`}; !reflect.DeepEqual(expected, runner.Messages(sdk.FileInput)) {
+		t.Fatalf(`expected %v but got %v`, expected, runner.Messages(sdk.FileInput))
+	}
+	if messages := runner.Messages(sdk.FileOutput); len(messages) != 0 {
+		t.Fatalf(`expected no messages but got %v`, messages)
+	}
+	if expected := []float64{0.5, 1.0}; !reflect.DeepEqual(expected, runner.ProgressUpdates()) {
+		t.Fatalf(`expected %v but got %v`, expected, runner.ProgressUpdates())
+	}
+	if expected := []string{`<Configuration><Updated /></Configuration>`}; !reflect.DeepEqual(expected, runner.ToolConfigUpdates()) {
+		t.Fatalf(`expected %v but got %v`, expected, runner.ToolConfigUpdates())
+	}
+
+	counts := runner.Messages(sdk.RecordCountString)
+	if len(counts) == 0 || !strings.HasPrefix(counts[len(counts)-1], `Output|4|`) {
+		t.Fatalf(`expected a final record count of 4 for Output but got %v`, counts)
+	}
+	all := runner.AllMessages()
+	if all[0].Status != sdk.Info || all[0].Message != `starting` {
+		t.Fatalf(`expected the first message to be Info 'starting' but got %v`, all[0])
+	}
+}
@@ -1,11 +1,19 @@
 package sdk
 
 type testOptions struct {
-	updateOnly  bool
-	updateMode  string
-	workflowDir string
-	locale      string
-	noCache     bool
+	updateOnly         bool
+	updateMode         string
+	workflowDir        string
+	locale             string
+	noCache            bool
+	noBrowseEverywhere bool
+	presorts           map[string]PresortInfo
+	recordLimit        int
+	cancelAfter        int
+	recoverPanics      bool
+	configMigrator     *ConfigMigrator
+	decryptor          PasswordDecryptor
+	tempFileDir        string
 }
 
 type OptionSetter func(testOptions) testOptions
@@ -44,3 +52,62 @@ func NoCache(value bool) OptionSetter {
 		return options
 	}
 }
+
+func NoBrowseEverywhere(value bool) OptionSetter {
+	return func(options testOptions) testOptions {
+		options.noBrowseEverywhere = value
+		return options
+	}
+}
+
+func Presort(anchor string, info PresortInfo) OptionSetter {
+	return func(options testOptions) testOptions {
+		if options.presorts == nil {
+			options.presorts = make(map[string]PresortInfo)
+		}
+		options.presorts[anchor] = info
+		return options
+	}
+}
+
+func RecordLimit(value int) OptionSetter {
+	return func(options testOptions) testOptions {
+		options.recordLimit = value
+		return options
+	}
+}
+
+func CancelAfterProgress(updates int) OptionSetter {
+	return func(options testOptions) testOptions {
+		options.cancelAfter = updates
+		return options
+	}
+}
+
+func RecoverPanics(value bool) OptionSetter {
+	return func(options testOptions) testOptions {
+		options.recoverPanics = value
+		return options
+	}
+}
+
+func MigrateConfig(migrator ConfigMigrator) OptionSetter {
+	return func(options testOptions) testOptions {
+		options.configMigrator = &migrator
+		return options
+	}
+}
+
+func DecryptPasswordsWith(decryptor PasswordDecryptor) OptionSetter {
+	return func(options testOptions) testOptions {
+		options.decryptor = decryptor
+		return options
+	}
+}
+
+func TempFileDir(dir string) OptionSetter {
+	return func(options testOptions) testOptions {
+		options.tempFileDir = dir
+		return options
+	}
+}
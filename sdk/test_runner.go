@@ -3,61 +3,119 @@ package sdk
 import (
 	"bufio"
 	"fmt"
+	b "github.com/tlarsendataguy/goalteryx/sdk/field_base"
 	"github.com/tlarsendataguy/goalteryx/sdk/import_file"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
 type FileTestRunner struct {
-	noCache bool
-	io      *testIo
-	plugin  *goPluginSharedMemory
-	inputs  map[string]*FilePusher
+	noCache     bool
+	io          *testIo
+	plugin      *goPluginSharedMemory
+	inputs      []*FilePusher
+	presorts    map[string]PresortInfo
+	engine      *testEngine
+	messages    *testMessageLog
+	recordLimit int
+	adapter     *pluginAdapter
 }
 
 func (r *FileTestRunner) SimulateLifecycle() {
 	if len(r.inputs) == 0 {
-		simulateInputLifecycle(r.plugin.ayxInterface)
+		simulateInputLifecycle(r.plugin.ayxInterface, r.recordLimit)
 	} else {
 		for _, pusher := range r.inputs {
-			simulateInputLifecycle(pusher.sharedMemory.ayxInterface)
+			simulateInputLifecycle(pusher.sharedMemory.ayxInterface, -1)
 		}
+		closePlugin(r.plugin.ayxInterface)
 	}
+	for _, harness := range r.engine.harnesses {
+		closePlugin(harness.ayxInterface)
+	}
+	r.engine.harnesses = nil
+}
+
+func (r *FileTestRunner) Err() error {
+	if r.adapter == nil {
+		return nil
+	}
+	return r.adapter.err
 }
 
 func (r *FileTestRunner) CaptureOutgoingAnchor(name string) *RecordCollector {
 	collector := &RecordCollector{}
 	sharedMemory := registerTestHarness(collector, r.noCache)
+	r.engine.harnesses = append(r.engine.harnesses, sharedMemory)
 
 	ii := generateIncomingConnectionInterface()
 	if r.noCache {
-		callPiAddIncomingConnectionNoCache(sharedMemory, name, ii)
+		callPiAddIncomingConnectionNoCache(sharedMemory, name, ``, ii)
 	} else {
-		callPiAddIncomingConnection(sharedMemory, name, ii)
+		callPiAddIncomingConnection(sharedMemory, name, ``, ii)
 	}
 	callPiAddOutgoingConnection(r.plugin, name, ii)
 
 	return collector
 }
 
+func (r *FileTestRunner) CaptureBrowseEverywhere(name string) *RecordCollector {
+	collector := &RecordCollector{}
+	r.engine.browseEverywhere[name] = collector
+	return collector
+}
+
 func (r *FileTestRunner) ConnectInput(name string, dataFile string) {
-	pusher := &FilePusher{file: dataFile}
+	if strings.EqualFold(filepath.Ext(dataFile), `.yxdb`) {
+		r.connectPusher(name, func() ([]b.FieldBase, []import_file.FileData) {
+			return loadYxdbFile(dataFile)
+		})
+		return
+	}
+	r.connectPusher(name, func() ([]b.FieldBase, []import_file.FileData) {
+		return loadDelimitedFile(dataFile)
+	})
+}
+
+func (r *FileTestRunner) connectPusher(name string, load func() ([]b.FieldBase, []import_file.FileData)) {
+	pusher := &FilePusher{load: load}
+	if presort, ok := r.presorts[name]; ok {
+		pusher.presort = &presort
+	}
 	sharedMemory := registerTestHarness(pusher, r.noCache)
 	pusher.sharedMemory = sharedMemory
 
+	connectionName := fmt.Sprintf(`#%v`, r.countInputs(name)+1)
+	pusher.anchor = name
+
 	ii := generateIncomingConnectionInterface()
 	if r.noCache {
-		callPiAddIncomingConnectionNoCache(r.plugin, name, ii)
+		callPiAddIncomingConnectionNoCache(r.plugin, name, connectionName, ii)
 	} else {
-		callPiAddIncomingConnection(r.plugin, name, ii)
+		callPiAddIncomingConnection(r.plugin, name, connectionName, ii)
 	}
 	callPiAddOutgoingConnection(sharedMemory, `Output`, ii)
 
-	r.inputs[name] = pusher
+	r.inputs = append(r.inputs, pusher)
+}
+
+func (r *FileTestRunner) countInputs(anchor string) int {
+	count := 0
+	for _, pusher := range r.inputs {
+		if pusher.anchor == anchor {
+			count++
+		}
+	}
+	return count
 }
 
 type FilePusher struct {
-	file         string
+	load         func() ([]b.FieldBase, []import_file.FileData)
+	anchor       string
+	presort      *PresortInfo
 	sharedMemory *goPluginSharedMemory
 	output       OutputAnchor
 	provider     Provider
@@ -77,28 +135,18 @@ func (f *FilePusher) OnRecordPacket(_ InputConnection) {
 }
 
 func (f *FilePusher) OnComplete() {
-	file, err := os.Open(f.file)
-	if err != nil {
-		panic(fmt.Sprintf(`error opening data file: %v`, err.Error()))
-	}
-
-	scanner := bufio.NewScanner(file)
-	success := scanner.Scan()
-	if !success {
+	fields, rows := f.load()
+	if fields == nil {
 		return
 	}
-	fieldNames := import_file.Preprocess(scanner.Bytes())
-	success = scanner.Scan()
-	if !success {
-		return
+	if f.presort != nil {
+		fields = presortFileData(fields, rows, *f.presort)
 	}
-	fieldTypes := import_file.Preprocess(scanner.Bytes())
 
-	extractor := import_file.NewExtractor(fieldNames, fieldTypes)
 	infoEditor := &EditingRecordInfo{}
 	source := `FilePusher`
 
-	for _, field := range extractor.Fields() {
+	for _, field := range fields {
 		switch field.Type {
 		case `Bool`:
 			infoEditor.AddBoolField(field.Name, source)
@@ -139,49 +187,82 @@ func (f *FilePusher) OnComplete() {
 	outInfo := infoEditor.GenerateOutgoingRecordInfo()
 	f.output.Open(outInfo)
 
-	for scanner.Scan() {
-		preprocessed := import_file.Preprocess(scanner.Bytes())
-		data := extractor.Extract(preprocessed)
+	for _, data := range rows {
 		for fieldName, value := range data.BlobFields {
+			field, ok := outInfo.BlobFields[fieldName]
+			if !ok {
+				continue
+			}
 			if value == nil {
-				outInfo.BlobFields[fieldName].SetNull()
+				field.SetNull()
 			} else {
-				outInfo.BlobFields[fieldName].SetBlob(value.([]byte))
+				field.SetBlob(value.([]byte))
 			}
 		}
 		for fieldName, value := range data.BoolFields {
+			field, ok := outInfo.BoolFields[fieldName]
+			if !ok {
+				continue
+			}
 			if value == nil {
-				outInfo.BoolFields[fieldName].SetNull()
+				field.SetNull()
 			} else {
-				outInfo.BoolFields[fieldName].SetBool(value.(bool))
+				field.SetBool(value.(bool))
 			}
 		}
 		for fieldName, value := range data.IntFields {
+			field, ok := outInfo.IntFields[fieldName]
+			if !ok {
+				continue
+			}
 			if value == nil {
-				outInfo.IntFields[fieldName].SetNull()
+				field.SetNull()
 			} else {
-				outInfo.IntFields[fieldName].SetInt(value.(int))
+				field.SetInt(value.(int))
 			}
 		}
 		for fieldName, value := range data.DecimalFields {
+			if decimal, ok := value.(Decimal); ok {
+				field, ok := outInfo.DecimalFields[fieldName]
+				if !ok {
+					continue
+				}
+				err := field.SetDecimal(decimal)
+				if err != nil {
+					panic(err.Error())
+				}
+				continue
+			}
+			field, ok := outInfo.FloatFields[fieldName]
+			if !ok {
+				continue
+			}
 			if value == nil {
-				outInfo.FloatFields[fieldName].SetNull()
+				field.SetNull()
 			} else {
-				outInfo.FloatFields[fieldName].SetFloat(value.(float64))
+				field.SetFloat(value.(float64))
 			}
 		}
 		for fieldName, value := range data.DateTimeFields {
+			field, ok := outInfo.DateTimeFields[fieldName]
+			if !ok {
+				continue
+			}
 			if value == nil {
-				outInfo.DateTimeFields[fieldName].SetNull()
+				field.SetNull()
 			} else {
-				outInfo.DateTimeFields[fieldName].SetDateTime(value.(time.Time))
+				field.SetDateTime(value.(time.Time))
 			}
 		}
 		for fieldName, value := range data.StringFields {
+			field, ok := outInfo.StringFields[fieldName]
+			if !ok {
+				continue
+			}
 			if value == nil {
-				outInfo.StringFields[fieldName].SetNull()
+				field.SetNull()
 			} else {
-				outInfo.StringFields[fieldName].SetString(value.(string))
+				field.SetString(value.(string))
 			}
 		}
 		f.output.Write()
@@ -189,15 +270,130 @@ func (f *FilePusher) OnComplete() {
 	f.output.UpdateProgress(1.0)
 }
 
+func loadDelimitedFile(dataFile string) ([]b.FieldBase, []import_file.FileData) {
+	file, err := os.Open(dataFile)
+	if err != nil {
+		panic(fmt.Sprintf(`error opening data file: %v`, err.Error()))
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	success := scanner.Scan()
+	if !success {
+		return nil, nil
+	}
+	fieldNames := import_file.Preprocess(scanner.Bytes())
+	success = scanner.Scan()
+	if !success {
+		return nil, nil
+	}
+	fieldTypes := import_file.Preprocess(scanner.Bytes())
+
+	extractor := import_file.NewExtractor(fieldNames, fieldTypes)
+	var rows []import_file.FileData
+	for scanner.Scan() {
+		preprocessed := import_file.Preprocess(scanner.Bytes())
+		rows = append(rows, extractor.Extract(preprocessed))
+	}
+	return extractor.Fields(), rows
+}
+
+func loadYxdbFile(dataFile string) ([]b.FieldBase, []import_file.FileData) {
+	reader, err := OpenYxdb(dataFile)
+	if err != nil {
+		panic(fmt.Sprintf(`error opening yxdb file: %v`, err.Error()))
+	}
+	defer reader.Close()
+
+	fields := reader.Metadata().Fields()
+	getters := recordValueGetters(reader.Metadata())
+	var rows []import_file.FileData
+	for reader.Next() {
+		row := make([]interface{}, len(getters))
+		for index, getter := range getters {
+			row[index] = getter(reader.Record())
+		}
+		rows = append(rows, inputRowToFileData(fields, row))
+	}
+	if reader.Err() != nil {
+		panic(fmt.Sprintf(`error reading yxdb file: %v`, reader.Err().Error()))
+	}
+	return fields, rows
+}
+
+func recordValueGetters(info IncomingRecordInfo) []func(Record) interface{} {
+	getters := make([]func(Record) interface{}, info.NumFields())
+	for index, field := range info.Fields() {
+		switch field.Type {
+		case `Bool`:
+			boolField, _ := info.GetBoolField(field.Name)
+			getters[index] = func(record Record) interface{} {
+				value, isNull := boolField.GetValue(record)
+				return nullableValue(value, isNull)
+			}
+		case `Byte`, `Int16`, `Int32`, `Int64`:
+			intField, _ := info.GetIntField(field.Name)
+			getters[index] = func(record Record) interface{} {
+				value, isNull := intField.GetValue(record)
+				return nullableValue(value, isNull)
+			}
+		case `Float`, `Double`:
+			floatField, _ := info.GetFloatField(field.Name)
+			getters[index] = func(record Record) interface{} {
+				value, isNull := floatField.GetValue(record)
+				return nullableValue(value, isNull)
+			}
+		case `FixedDecimal`:
+			decimalField, _ := info.GetDecimalField(field.Name)
+			getters[index] = func(record Record) interface{} {
+				value, isNull := decimalField.GetValue(record)
+				return nullableValue(value, isNull)
+			}
+		case `String`, `WString`, `V_String`, `V_WString`:
+			stringField, _ := info.GetStringField(field.Name)
+			getters[index] = func(record Record) interface{} {
+				value, isNull := stringField.GetValue(record)
+				return nullableValue(value, isNull)
+			}
+		case `Date`, `DateTime`, `Time`:
+			timeField, _ := info.GetTimeField(field.Name)
+			getters[index] = func(record Record) interface{} {
+				value, isNull := timeField.GetValue(record)
+				return nullableValue(value, isNull)
+			}
+		case `Blob`, `SpatialObj`:
+			blobField, _ := info.GetBlobField(field.Name)
+			getters[index] = func(record Record) interface{} {
+				value := blobField.GetValue(record)
+				if value == nil {
+					return nil
+				}
+				return append([]byte{}, value...)
+			}
+		}
+	}
+	return getters
+}
+
+func nullableValue(value interface{}, isNull bool) interface{} {
+	if isNull {
+		return nil
+	}
+	return value
+}
+
 type RecordCollector struct {
 	Config          IncomingRecordInfo
 	Name            string
 	Data            map[string][]interface{}
 	Progress        float64
 	PacketsReceived int
+	stopAfter       int
+	stops           bool
 	boolFields      map[string]BoolGetter
 	intFields       map[string]IntGetter
 	floatFields     map[string]FloatGetter
+	decimalFields   map[string]DecimalGetter
 	stringFields    map[string]StringGetter
 	timeFields      map[string]TimeGetter
 	blobFields      map[string]BytesGetter
@@ -208,12 +404,21 @@ func (r *RecordCollector) Init(_ Provider) {
 	r.boolFields = make(map[string]BoolGetter)
 	r.intFields = make(map[string]IntGetter)
 	r.floatFields = make(map[string]FloatGetter)
+	r.decimalFields = make(map[string]DecimalGetter)
 	r.stringFields = make(map[string]StringGetter)
 	r.timeFields = make(map[string]TimeGetter)
 	r.blobFields = make(map[string]BytesGetter)
 }
 
+func (r *RecordCollector) StopAfter(records int) {
+	r.stopAfter = records
+	r.stops = true
+}
+
 func (r *RecordCollector) OnInputConnectionOpened(connection InputConnection) {
+	if r.stops {
+		setAcceptLimit(connection, r.stopAfter)
+	}
 	r.Name = connection.Name()
 	r.Config = connection.Metadata()
 	for _, field := range r.Config.Fields() {
@@ -225,9 +430,12 @@ func (r *RecordCollector) OnInputConnectionOpened(connection InputConnection) {
 		case `Byte`, `Int16`, `Int32`, `Int64`:
 			intField, _ := r.Config.GetIntField(field.Name)
 			r.intFields[field.Name] = intField.GetValue
-		case `Float`, `Double`, `FixedDecimal`:
+		case `Float`, `Double`:
 			floatField, _ := r.Config.GetFloatField(field.Name)
 			r.floatFields[field.Name] = floatField.GetValue
+		case `FixedDecimal`:
+			decimalField, _ := r.Config.GetDecimalField(field.Name)
+			r.decimalFields[field.Name] = decimalField.GetValue
 		case `String`, `WString`, `V_String`, `V_WString`:
 			stringField, _ := r.Config.GetStringField(field.Name)
 			r.stringFields[field.Name] = stringField.GetValue
@@ -268,6 +476,10 @@ func (r *RecordCollector) OnRecordPacket(connection InputConnection) {
 			value, isNull := getter(record)
 			r.appendDataToField(name, value, isNull)
 		}
+		for name, getter := range r.decimalFields {
+			value, isNull := getter(record)
+			r.appendDataToField(name, value, isNull)
+		}
 		for name, getter := range r.stringFields {
 			value, isNull := getter(record)
 			r.appendDataToField(name, value, isNull)
@@ -282,6 +494,15 @@ func (r *RecordCollector) OnRecordPacket(connection InputConnection) {
 
 func (r *RecordCollector) OnComplete() {}
 
+func setAcceptLimit(connection InputConnection, records int) {
+	switch typed := connection.(type) {
+	case *ImpInputConnection:
+		typed.data.acceptLimit = int64(records)
+	case *ImpInputConnectionNoCache:
+		typed.data.acceptLimit = int64(records)
+	}
+}
+
 func (r *RecordCollector) appendDataToField(fieldName string, value interface{}, isNull bool) {
 	if isNull {
 		r.Data[fieldName] = append(r.Data[fieldName], nil)
@@ -289,3 +510,56 @@ func (r *RecordCollector) appendDataToField(fieldName string, value interface{},
 		r.Data[fieldName] = append(r.Data[fieldName], value)
 	}
 }
+
+func presortFileData(fields []b.FieldBase, rows []import_file.FileData, info PresortInfo) []b.FieldBase {
+	for _, sortField := range info.SortFields {
+		if !hasField(fields, sortField.Field) {
+			panic(fmt.Sprintf(`presort field '%v' does not exist in the input data`, sortField.Field))
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, sortField := range info.SortFields {
+			compared := compareSortValues(fileDataValue(rows[i], sortField.Field), fileDataValue(rows[j], sortField.Field))
+			if compared == 0 {
+				continue
+			}
+			if sortField.Order == Descending {
+				return compared > 0
+			}
+			return compared < 0
+		}
+		return false
+	})
+
+	if len(info.SelectFields) == 0 {
+		return fields
+	}
+	selected := make([]b.FieldBase, 0, len(info.SelectFields))
+	for _, field := range fields {
+		for _, name := range info.SelectFields {
+			if field.Name == name {
+				selected = append(selected, field)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func hasField(fields []b.FieldBase, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func fileDataValue(data import_file.FileData, name string) interface{} {
+	for _, values := range []map[string]interface{}{data.BoolFields, data.IntFields, data.DecimalFields, data.StringFields, data.DateTimeFields, data.BlobFields} {
+		if value, ok := values[name]; ok {
+			return value
+		}
+	}
+	return nil
+}
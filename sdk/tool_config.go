@@ -0,0 +1,393 @@
+package sdk
+
+import (
+	"encoding/xml"
+	"fmt"
+	c "github.com/tlarsendataguy/goalteryx/sdk/config_tag"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const configRootName = `Configuration`
+
+type ConfigError struct {
+	Field   string
+	Message string
+}
+
+func (e ConfigError) Error() string {
+	return fmt.Sprintf(`%v: %v`, e.Field, e.Message)
+}
+
+type ConfigErrors []ConfigError
+
+func (e ConfigErrors) Error() string {
+	messages := make([]string, len(e))
+	for index, err := range e {
+		messages[index] = err.Error()
+	}
+	return fmt.Sprintf(`invalid tool configuration: %v`, strings.Join(messages, `; `))
+}
+
+type configElement struct {
+	name     string
+	attrs    []xml.Attr
+	text     string
+	children []*configElement
+}
+
+func BindToolConfig(provider Provider, config interface{}) error {
+	err := bindToolConfig(provider.ToolConfig(), config, provider.Io())
+	reportConfigError(provider.Io(), err)
+	return err
+}
+
+func WriteToolConfig(provider Provider, config interface{}) error {
+	configValue := reflect.ValueOf(config)
+	if configValue.Kind() == reflect.Ptr {
+		configValue = configValue.Elem()
+	}
+	if configValue.Kind() != reflect.Struct {
+		return fmt.Errorf(`WriteToolConfig requires a struct or a pointer to a struct but got %v`, reflect.TypeOf(config))
+	}
+	root, err := parseConfigXml(provider.ToolConfig())
+	if err != nil {
+		return err
+	}
+	err = writeConfigElement(root, configValue)
+	if err != nil {
+		return err
+	}
+	provider.Environment().UpdateToolConfig(root.String())
+	return nil
+}
+
+func bindToolConfig(configXml string, config interface{}, io Io) error {
+	configValue := reflect.ValueOf(config)
+	if configValue.Kind() != reflect.Ptr || configValue.IsNil() || configValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf(`BindToolConfig requires a non-nil pointer to a struct but got %v`, reflect.TypeOf(config))
+	}
+	root, err := parseConfigXml(configXml)
+	if err != nil {
+		return err
+	}
+	var errs ConfigErrors
+	err = bindConfigElement(root, configValue.Elem(), ``, io, &errs)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func reportConfigError(io Io, err error) {
+	if err == nil {
+		return
+	}
+	if errs, ok := err.(ConfigErrors); ok {
+		for _, configErr := range errs {
+			io.Error(configErr.Error())
+		}
+		return
+	}
+	io.Error(err.Error())
+}
+
+func bindConfigElement(element *configElement, dest reflect.Value, path string, io Io, errs *ConfigErrors) error {
+	fields, err := c.Fields(dest.Type())
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		fieldPath := field.Tag.Name
+		if path != `` {
+			fieldPath = path + `.` + field.Tag.Name
+		}
+		fieldValue := dest.FieldByIndex(field.Index)
+		matches := element.childrenNamed(field.Tag.Name)
+
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			child := &configElement{name: field.Tag.Name}
+			if len(matches) > 0 {
+				child = matches[0]
+			} else if field.Tag.Required {
+				*errs = append(*errs, ConfigError{Field: fieldPath, Message: `is required`})
+				continue
+			}
+			err = bindConfigElement(child, fieldValue, fieldPath, io, errs)
+			if err != nil {
+				return err
+			}
+		case reflect.Slice:
+			var values []string
+			for _, match := range matches {
+				if value := match.value(); strings.TrimSpace(value) != `` {
+					values = append(values, value)
+				}
+			}
+			if len(values) == 0 && field.Tag.HasDefault {
+				values = []string{field.Tag.DefaultValue}
+			}
+			if len(values) == 0 && field.Tag.Required {
+				*errs = append(*errs, ConfigError{Field: fieldPath, Message: `is required`})
+				continue
+			}
+			slice := reflect.MakeSlice(field.Type, len(values), len(values))
+			for index, value := range values {
+				if configErr := setConfigValue(slice.Index(index), value, field.Tag, io); configErr != `` {
+					*errs = append(*errs, ConfigError{Field: fieldPath, Message: configErr})
+				}
+			}
+			fieldValue.Set(slice)
+		default:
+			value := ``
+			if len(matches) > 0 {
+				value = matches[0].value()
+			}
+			if strings.TrimSpace(value) == `` {
+				if field.Tag.HasDefault {
+					value = field.Tag.DefaultValue
+				} else {
+					if field.Tag.Required {
+						*errs = append(*errs, ConfigError{Field: fieldPath, Message: `is required`})
+					}
+					continue
+				}
+			}
+			if configErr := setConfigValue(fieldValue, value, field.Tag, io); configErr != `` {
+				*errs = append(*errs, ConfigError{Field: fieldPath, Message: configErr})
+			}
+		}
+	}
+	return nil
+}
+
+func setConfigValue(target reflect.Value, value string, tag c.Tag, io Io) string {
+	if len(tag.Enum) > 0 && !isConfigEnumValue(tag.Enum, value) {
+		return fmt.Sprintf(`expected one of [%v] but got '%v'`, strings.Join(tag.Enum, `, `), value)
+	}
+	trimmed := strings.TrimSpace(value)
+	switch kind := target.Kind(); {
+	case kind == reflect.String:
+		if tag.Password {
+			value = io.DecryptPassword(value)
+		}
+		target.SetString(value)
+	case kind == reflect.Bool:
+		boolValue, err := strconv.ParseBool(trimmed)
+		if err != nil {
+			return fmt.Sprintf(`expected a boolean but got '%v'`, value)
+		}
+		target.SetBool(boolValue)
+	case kind == reflect.Uint || kind == reflect.Uint8 || kind == reflect.Uint16 || kind == reflect.Uint32 || kind == reflect.Uint64:
+		uintValue, err := strconv.ParseUint(trimmed, 10, target.Type().Bits())
+		if err != nil {
+			return fmt.Sprintf(`expected a whole number but got '%v'`, value)
+		}
+		target.SetUint(uintValue)
+	case isIntKind(kind):
+		intValue, err := strconv.ParseInt(trimmed, 10, target.Type().Bits())
+		if err != nil {
+			return fmt.Sprintf(`expected a whole number but got '%v'`, value)
+		}
+		target.SetInt(intValue)
+	case isFloatKind(kind):
+		floatValue, err := strconv.ParseFloat(trimmed, target.Type().Bits())
+		if err != nil {
+			return fmt.Sprintf(`expected a number but got '%v'`, value)
+		}
+		target.SetFloat(floatValue)
+	}
+	return ``
+}
+
+func isConfigEnumValue(enum []string, value string) bool {
+	for _, option := range enum {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+func writeConfigElement(element *configElement, source reflect.Value) error {
+	fields, err := c.Fields(source.Type())
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if field.Tag.Password {
+			continue
+		}
+		fieldValue := source.FieldByIndex(field.Index)
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			matches := element.childrenNamed(field.Tag.Name)
+			if len(matches) == 0 {
+				matches = element.replaceChildren(field.Tag.Name, []string{``})
+			}
+			err = writeConfigElement(matches[0], fieldValue)
+			if err != nil {
+				return err
+			}
+		case reflect.Slice:
+			values := make([]string, fieldValue.Len())
+			for index := range values {
+				values[index] = formatConfigValue(fieldValue.Index(index))
+			}
+			element.replaceChildren(field.Tag.Name, values)
+		default:
+			matches := element.childrenNamed(field.Tag.Name)
+			if len(matches) == 0 {
+				element.replaceChildren(field.Tag.Name, []string{formatConfigValue(fieldValue)})
+				continue
+			}
+			matches[0].setValue(formatConfigValue(fieldValue))
+		}
+	}
+	return nil
+}
+
+func formatConfigValue(value reflect.Value) string {
+	switch kind := value.Kind(); {
+	case kind == reflect.Bool:
+		if value.Bool() {
+			return `True`
+		}
+		return `False`
+	case kind == reflect.Uint || kind == reflect.Uint8 || kind == reflect.Uint16 || kind == reflect.Uint32 || kind == reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case isIntKind(kind):
+		return strconv.FormatInt(value.Int(), 10)
+	case isFloatKind(kind):
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits())
+	default:
+		return value.String()
+	}
+}
+
+func parseConfigXml(configXml string) (*configElement, error) {
+	if strings.TrimSpace(configXml) == `` {
+		return &configElement{name: configRootName}, nil
+	}
+	decoder := xml.NewDecoder(strings.NewReader(configXml))
+	var root *configElement
+	var stack []*configElement
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if root != nil && len(stack) == 0 {
+				return root, nil
+			}
+			return nil, fmt.Errorf(`error parsing tool configuration: %v`, err.Error())
+		}
+		switch typed := token.(type) {
+		case xml.StartElement:
+			element := &configElement{name: typed.Name.Local, attrs: typed.Copy().Attr}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf(`error parsing tool configuration: multiple root elements`)
+				}
+				root = element
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, element)
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(typed)
+			}
+		}
+	}
+}
+
+func (e *configElement) childrenNamed(name string) []*configElement {
+	var children []*configElement
+	for _, child := range e.children {
+		if child.name == name {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+func (e *configElement) replaceChildren(name string, values []string) []*configElement {
+	position := -1
+	children := make([]*configElement, 0, len(e.children))
+	for _, child := range e.children {
+		if child.name == name {
+			if position == -1 {
+				position = len(children)
+			}
+			continue
+		}
+		children = append(children, child)
+	}
+	if position == -1 {
+		position = len(children)
+	}
+	replacements := make([]*configElement, len(values))
+	for index, value := range values {
+		replacements[index] = &configElement{name: name, text: value}
+	}
+	e.children = append(children[:position], append(replacements, children[position:]...)...)
+	return replacements
+}
+
+func (e *configElement) valueAttr() *xml.Attr {
+	for index := range e.attrs {
+		if e.attrs[index].Name.Local == `value` {
+			return &e.attrs[index]
+		}
+	}
+	return nil
+}
+
+func (e *configElement) value() string {
+	if attr := e.valueAttr(); attr != nil && strings.TrimSpace(e.text) == `` {
+		return attr.Value
+	}
+	return e.text
+}
+
+func (e *configElement) setValue(value string) {
+	if attr := e.valueAttr(); attr != nil && strings.TrimSpace(e.text) == `` {
+		attr.Value = value
+		return
+	}
+	e.text = value
+}
+
+func (e *configElement) String() string {
+	builder := &strings.Builder{}
+	e.write(builder)
+	return builder.String()
+}
+
+func (e *configElement) write(builder *strings.Builder) {
+	builder.WriteString(`<` + e.name)
+	for _, attr := range e.attrs {
+		builder.WriteString(` ` + attr.Name.Local + `="`)
+		_ = xml.EscapeText(builder, []byte(attr.Value))
+		builder.WriteString(`"`)
+	}
+	if len(e.children) == 0 && e.text == `` {
+		builder.WriteString(` />`)
+		return
+	}
+	builder.WriteString(`>`)
+	if len(e.children) == 0 {
+		_ = xml.EscapeText(builder, []byte(e.text))
+	}
+	for _, child := range e.children {
+		child.write(builder)
+	}
+	builder.WriteString(`</` + e.name + `>`)
+}
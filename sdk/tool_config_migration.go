@@ -0,0 +1,140 @@
+package sdk
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const configVersionAttr = `version`
+
+type ConfigMigration func(configXml string) (string, error)
+
+type ConfigMigrator struct {
+	Version    int
+	Migrations map[int]ConfigMigration
+	Persist    bool
+}
+
+func (m ConfigMigrator) Migrate(configXml string) (string, bool, error) {
+	if strings.TrimSpace(configXml) == `` {
+		return configXml, false, nil
+	}
+	root, err := parseConfigXml(configXml)
+	if err != nil {
+		return ``, false, err
+	}
+	version, err := root.version()
+	if err != nil {
+		return ``, false, err
+	}
+	if version == m.Version {
+		return configXml, false, nil
+	}
+	if version > m.Version {
+		return ``, false, fmt.Errorf(`tool configuration version %v is newer than the tool's version %v`, version, m.Version)
+	}
+	for ; version < m.Version; version++ {
+		migration, ok := m.Migrations[version]
+		if !ok {
+			return ``, false, fmt.Errorf(`no migration is registered for tool configuration version %v`, version)
+		}
+		configXml, err = migration(configXml)
+		if err != nil {
+			return ``, false, fmt.Errorf(`error migrating tool configuration from version %v: %v`, version, err.Error())
+		}
+	}
+	_, err = parseConfigXml(configXml)
+	if err != nil {
+		return ``, false, err
+	}
+	configXml, err = setRootAttr(configXml, configVersionAttr, strconv.Itoa(m.Version))
+	if err != nil {
+		return ``, false, err
+	}
+	return configXml, true, nil
+}
+
+func migrateToolConfig(data *goPluginSharedMemory, provider Provider, migrator *ConfigMigrator) {
+	if migrator == nil {
+		return
+	}
+	callPlugin(data, func() {
+		migrated, changed, err := migrator.Migrate(provider.ToolConfig())
+		if err != nil {
+			failPlugin(data, err.Error())
+			return
+		}
+		if !changed {
+			return
+		}
+		if migrator.Persist {
+			provider.Environment().UpdateToolConfig(migrated)
+			return
+		}
+		updateConfig(data, migrated)
+	})
+}
+
+func (e *configElement) version() (int, error) {
+	for _, attr := range e.attrs {
+		if attr.Name.Local != configVersionAttr {
+			continue
+		}
+		version, err := strconv.Atoi(strings.TrimSpace(attr.Value))
+		if err != nil {
+			return 0, fmt.Errorf(`tool configuration version '%v' is not a whole number`, attr.Value)
+		}
+		return version, nil
+	}
+	return 1, nil
+}
+
+func setRootAttr(configXml string, name string, value string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(configXml))
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err != nil {
+			return ``, fmt.Errorf(`error parsing tool configuration: %v`, err.Error())
+		}
+		root, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		end := decoder.InputOffset()
+
+		found := false
+		for index := range root.Attr {
+			if root.Attr[index].Name.Space == `` && root.Attr[index].Name.Local == name {
+				root.Attr[index].Value = value
+				found = true
+			}
+		}
+		if !found {
+			root.Attr = append(root.Attr, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+		}
+
+		builder := &strings.Builder{}
+		builder.WriteString(`<` + rawName(root.Name))
+		for _, attr := range root.Attr {
+			builder.WriteString(` ` + rawName(attr.Name) + `="`)
+			_ = xml.EscapeText(builder, []byte(attr.Value))
+			builder.WriteString(`"`)
+		}
+		if strings.HasSuffix(configXml[start:end], `/>`) {
+			builder.WriteString(` />`)
+		} else {
+			builder.WriteString(`>`)
+		}
+		return configXml[:start] + builder.String() + configXml[end:], nil
+	}
+}
+
+func rawName(name xml.Name) string {
+	if name.Space == `` {
+		return name.Local
+	}
+	return name.Space + `:` + name.Local
+}
@@ -0,0 +1,159 @@
+package sdk_test
+
+import (
+	"fmt"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"strings"
+	"testing"
+)
+
+func splitServer(configXml string) (string, error) {
+	start := strings.Index(configXml, `<Server>`)
+	end := strings.Index(configXml, `</Server>`)
+	if start == -1 || end == -1 {
+		return ``, fmt.Errorf(`Server is missing`)
+	}
+	server := configXml[start+8 : end]
+	parts := strings.Split(server, `:`)
+	if len(parts) != 2 {
+		return ``, fmt.Errorf(`invalid server '%v'`, server)
+	}
+	configXml = strings.Replace(configXml, server, parts[0], 1)
+	return strings.Replace(configXml, `</Configuration>`, fmt.Sprintf(`<Port>%v</Port></Configuration>`, parts[1]), 1), nil
+}
+
+func splitFields(configXml string) (string, error) {
+	start := strings.Index(configXml, `<Fields>`)
+	end := strings.Index(configXml, `</Fields>`)
+	if start == -1 || end == -1 {
+		return configXml, nil
+	}
+	fields := strings.Split(configXml[start+8:end], `,`)
+	replacement := ``
+	for _, field := range fields {
+		replacement += fmt.Sprintf(`<Field>%v</Field>`, field)
+	}
+	return configXml[:start] + replacement + configXml[end+9:], nil
+}
+
+var testMigrator = sdk.ConfigMigrator{
+	Version: 3,
+	Migrations: map[int]sdk.ConfigMigration{
+		1: splitServer,
+		2: splitFields,
+	},
+}
+
+func TestVerifyConfigMigration(t *testing.T) {
+	err := sdk.VerifyConfigMigration(testMigrator, `sdk_test_config_v1.xml`, `sdk_test_config_v3.xml`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	err = sdk.VerifyConfigMigration(testMigrator, `sdk_test_config_v3.xml`, `sdk_test_config_v3.xml`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	err = sdk.VerifyConfigMigration(testMigrator, `sdk_test_config_v1.xml`, `sdk_test_config_v1.xml`)
+	if err == nil || !strings.Contains(err.Error(), `migrated config did not match expected config`) {
+		t.Fatalf(`expected a mismatch error but got %v`, err)
+	}
+}
+
+func TestMigrateConfigAtInit(t *testing.T) {
+	implementation := &TestImplementation{}
+	runner := sdk.RegisterToolTest(implementation, 1, `<Configuration version="2"><Server>db</Server><Fields>X</Fields></Configuration>`, sdk.MigrateConfig(testMigrator))
+
+	expected := `<Configuration version="3"><Server>db</Server><Field>X</Field></Configuration>`
+	if implementation.Config != expected {
+		t.Fatalf(`expected '%v' but got '%v'`, expected, implementation.Config)
+	}
+	if updates := runner.ToolConfigUpdates(); len(updates) != 0 {
+		t.Fatalf(`expected no config updates but got %v`, updates)
+	}
+}
+
+func TestMigrateConfigPersist(t *testing.T) {
+	migrator := testMigrator
+	migrator.Persist = true
+	implementation := &TestImplementation{}
+	runner := sdk.RegisterToolTest(implementation, 1, `<Configuration version="2"><Fields>X</Fields></Configuration>`, sdk.MigrateConfig(migrator))
+
+	expected := `<Configuration version="3"><Field>X</Field></Configuration>`
+	if updates := runner.ToolConfigUpdates(); len(updates) != 1 || updates[0] != expected {
+		t.Fatalf(`expected config update '%v' but got %v`, expected, updates)
+	}
+	if implementation.Config != expected {
+		t.Fatalf(`expected '%v' but got '%v'`, expected, implementation.Config)
+	}
+}
+
+func TestMigrateConfigFailure(t *testing.T) {
+	implementation := &TestImplementation{}
+	runner := sdk.RegisterToolTest(implementation, 1, `<Configuration><Server>no port</Server></Configuration>`, sdk.MigrateConfig(testMigrator))
+	runner.SimulateLifecycle()
+
+	if implementation.DidInit {
+		t.Fatalf(`expected Init to be skipped but it was called`)
+	}
+	expected := `error migrating tool configuration from version 1: invalid server 'no port'`
+	if errors := runner.Messages(sdk.Error); len(errors) != 1 || errors[0] != expected {
+		t.Fatalf(`expected '%v' but got %v`, expected, errors)
+	}
+}
+
+func TestMigrateNewerConfig(t *testing.T) {
+	_, _, err := testMigrator.Migrate(`<Configuration version="4" />`)
+	if err == nil || err.Error() != `tool configuration version 4 is newer than the tool's version 3` {
+		t.Fatalf(`expected a version error but got %v`, err)
+	}
+}
+
+func TestMigrateConfigPanicIsRecovered(t *testing.T) {
+	migrator := sdk.ConfigMigrator{
+		Version: 2,
+		Migrations: map[int]sdk.ConfigMigration{
+			1: func(_ string) (string, error) { panic(`migration failed`) },
+		},
+	}
+	implementation := &TestImplementation{}
+	runner := sdk.RegisterToolTest(implementation, 1, `<Configuration />`, sdk.MigrateConfig(migrator), sdk.RecoverPanics(true))
+	runner.SimulateLifecycle()
+
+	if implementation.DidInit {
+		t.Fatalf(`expected Init to be skipped but it was called`)
+	}
+	if errors := runner.Messages(sdk.Error); len(errors) != 1 || !strings.HasPrefix(errors[0], `migration failed`) {
+		t.Fatalf(`expected the migration panic to be reported but got %v`, errors)
+	}
+}
+
+func TestMigrateConfigKeepsUnchangedXml(t *testing.T) {
+	migrator := sdk.ConfigMigrator{
+		Version: 2,
+		Migrations: map[int]sdk.ConfigMigration{
+			1: func(configXml string) (string, error) {
+				return strings.Replace(configXml, `<Server>localhost:5432</Server>`, `<Server>localhost</Server>`, 1), nil
+			},
+		},
+	}
+	body := `
+  <!-- written by Designer -->
+  <Server>localhost:5432</Server>
+  <Query><![CDATA[SELECT * FROM [Table] WHERE Amount < 10 & Flag = 'Y']]></Query>
+  <Note>before <b>bold</b> after</Note>
+  <ayx:Extra ayx:kind="raw" />
+</Configuration>`
+	migrated, changed, err := migrator.Migrate(`<?xml version="1.0"?>` + "\n" + `<Configuration xmlns:ayx="urn:ayx">` + body)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	expected := `<?xml version="1.0"?>` + "\n" + `<Configuration xmlns:ayx="urn:ayx" version="2">` + strings.Replace(body, `localhost:5432`, `localhost`, 1)
+	if !changed || migrated != expected {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, migrated)
+	}
+
+	migrated, _, err = migrator.Migrate(`<Configuration version="1"/>`)
+	if err != nil || migrated != `<Configuration version="2" />` {
+		t.Fatalf(`expected '<Configuration version="2" />' but got '%v' and %v`, migrated, err)
+	}
+}
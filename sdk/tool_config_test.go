@@ -0,0 +1,146 @@
+package sdk_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type connectionConfig struct {
+	Server   string `ayx:"Server,required"`
+	Port     int    `ayx:"Port,default=5432"`
+	Password string `ayx:"Password,password"`
+}
+
+type toolConfig struct {
+	Mode       string           `ayx:"Mode,enum=Fast|Safe,default=Safe"`
+	Limit      uint16           `ayx:"Limit"`
+	Ratio      float64          `ayx:"Ratio,default=0.5"`
+	Verbose    bool             `ayx:"Verbose"`
+	Fields     []string         `ayx:"Field"`
+	Connection connectionConfig `ayx:"Connection"`
+	Ignored    string           `ayx:"-"`
+}
+
+func TestBindToolConfig(t *testing.T) {
+	implementation := &TestImplementation{}
+	sdk.RegisterToolTest(implementation, 1, `<Configuration>
+  <Mode>Fast</Mode>
+  <Limit>10</Limit>
+  <Verbose value="True" />
+  <Field>A</Field>
+  <Field>B</Field>
+  <Connection>
+    <Server>localhost</Server>
+    <Password>secret</Password>
+  </Connection>
+</Configuration>`)
+
+	config := toolConfig{}
+	err := sdk.BindToolConfig(implementation.Provider, &config)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	expected := toolConfig{
+		Mode:       `Fast`,
+		Limit:      10,
+		Ratio:      0.5,
+		Verbose:    true,
+		Fields:     []string{`A`, `B`},
+		Connection: connectionConfig{Server: `localhost`, Port: 5432, Password: `secret`},
+	}
+	if !reflect.DeepEqual(expected, config) {
+		t.Fatalf(`expected %v but got %v`, expected, config)
+	}
+}
+
+func TestBindToolConfigValidationErrors(t *testing.T) {
+	implementation := &TestImplementation{}
+	runner := sdk.RegisterToolTest(implementation, 1, `<Configuration><Mode>Slow</Mode><Limit>-1</Limit><Verbose>maybe</Verbose></Configuration>`)
+
+	config := toolConfig{}
+	err := sdk.BindToolConfig(implementation.Provider, &config)
+	errs, ok := err.(sdk.ConfigErrors)
+	if !ok {
+		t.Fatalf(`expected ConfigErrors but got %v`, err)
+	}
+	expected := sdk.ConfigErrors{
+		{Field: `Mode`, Message: `expected one of [Fast, Safe] but got 'Slow'`},
+		{Field: `Limit`, Message: `expected a whole number but got '-1'`},
+		{Field: `Verbose`, Message: `expected a boolean but got 'maybe'`},
+		{Field: `Connection.Server`, Message: `is required`},
+	}
+	if !reflect.DeepEqual(expected, errs) {
+		t.Fatalf(`expected %v but got %v`, expected, errs)
+	}
+	if messages := runner.Messages(sdk.Error); len(messages) != 4 || messages[3] != `Connection.Server: is required` {
+		t.Fatalf(`expected 4 error messages but got %v`, messages)
+	}
+}
+
+func TestBindToolConfigInvalidTag(t *testing.T) {
+	implementation := &TestImplementation{}
+	sdk.RegisterToolTest(implementation, 1, ``)
+
+	config := struct {
+		Value string `ayx:"Value,size=5"`
+	}{}
+	err := sdk.BindToolConfig(implementation.Provider, &config)
+	if err == nil || !strings.Contains(err.Error(), `invalid option 'size=5'`) {
+		t.Fatalf(`expected an invalid option error but got %v`, err)
+	}
+}
+
+func TestWriteToolConfig(t *testing.T) {
+	implementation := &TestImplementation{}
+	sdk.RegisterToolTest(implementation, 1, `<Configuration><Unknown>keep</Unknown><Verbose value="False" /><Field>A</Field><Connection><Server>old</Server><Password>encrypted</Password></Connection></Configuration>`)
+
+	config := toolConfig{
+		Mode:       `Fast`,
+		Limit:      3,
+		Ratio:      0.25,
+		Verbose:    true,
+		Fields:     []string{`X`, `Y & Z`},
+		Connection: connectionConfig{Server: `new`, Port: 1, Password: `decrypted`},
+	}
+	err := sdk.WriteToolConfig(implementation.Provider, config)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	expected := `<Configuration><Unknown>keep</Unknown><Verbose value="True" /><Field>X</Field><Field>Y &amp; Z</Field><Connection><Server>new</Server><Password>encrypted</Password><Port>1</Port></Connection><Mode>Fast</Mode><Limit>3</Limit><Ratio>0.25</Ratio></Configuration>`
+	if actual := implementation.Provider.ToolConfig(); actual != expected {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, actual)
+	}
+
+	roundTrip := toolConfig{}
+	err = sdk.BindToolConfig(implementation.Provider, &roundTrip)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	config.Connection.Password = `encrypted`
+	if !reflect.DeepEqual(config, roundTrip) {
+		t.Fatalf(`expected %v but got %v`, config, roundTrip)
+	}
+}
+
+func TestBindToolConfigDecryptsPasswords(t *testing.T) {
+	implementation := &TestImplementation{}
+	encrypted := sdk.EncryptTestPassword(`hello world`)
+	runner := sdk.RegisterToolTest(implementation, 1, `<Configuration><Connection><Server>db</Server><Password>`+encrypted+`</Password></Connection></Configuration>`, sdk.DecryptPasswordsWith(sdk.TestPasswordDecryptor{}))
+
+	config := toolConfig{}
+	err := sdk.BindToolConfig(implementation.Provider, &config)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if config.Connection.Password != `hello world` {
+		t.Fatalf(`expected 'hello world' but got '%v'`, config.Connection.Password)
+	}
+	if password := implementation.Provider.Io().DecryptPassword(`not encrypted`); password != `not encrypted` {
+		t.Fatalf(`expected 'not encrypted' but got '%v'`, password)
+	}
+	if errors := runner.Messages(sdk.Error); len(errors) != 1 || errors[0] != `password could not be decrypted` {
+		t.Fatalf(`expected a decryption error but got %v`, errors)
+	}
+}
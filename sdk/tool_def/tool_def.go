@@ -0,0 +1,258 @@
+package tool_def
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+const sdkVersion = `10.1`
+
+type Tool struct {
+	EngineDll   string
+	EntryPoint  string
+	Title       string
+	Description string
+	Category    string
+	SearchTags  []string
+	Version     string
+	Author      string
+	Company     string
+	Copyright   string
+	Icon        string
+	Help        string
+	Inputs      []Anchor
+	Outputs     []Anchor
+	Config      interface{}
+}
+
+type Anchor struct {
+	Name          string
+	Label         string
+	AllowMultiple bool
+	Optional      bool
+}
+
+func Generate(tool Tool, dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	name := filepath.Base(dir)
+	configXml, err := GenerateConfigXml(tool, name+`.html`)
+	if err != nil {
+		return err
+	}
+	html, err := GenerateHtml(tool)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, name+`Config.xml`), configXml, 0644)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, name+`.html`), html, 0644)
+}
+
+func Main(tool Tool) error {
+	dir := `.`
+	if len(os.Args) > 1 {
+		dir = os.Args[1]
+	}
+	return Generate(tool, dir)
+}
+
+type ConfigXml struct {
+	XMLName        xml.Name       `xml:"AlteryxJavaScriptPlugin"`
+	EngineSettings EngineSettings `xml:"EngineSettings"`
+	GuiSettings    GuiSettings    `xml:"GuiSettings"`
+	MetaInfo       MetaInfo       `xml:"Properties>MetaInfo"`
+}
+
+type EngineSettings struct {
+	EngineDll           string `xml:"EngineDll,attr"`
+	EngineDllEntryPoint string `xml:"EngineDllEntryPoint,attr"`
+	SDKVersion          string `xml:"SDKVersion,attr"`
+}
+
+type GuiSettings struct {
+	Html              string       `xml:"Html,attr"`
+	Icon              string       `xml:"Icon,attr"`
+	Help              string       `xml:"Help,attr"`
+	SDKVersion        string       `xml:"SDKVersion,attr"`
+	InputConnections  []Connection `xml:"InputConnections>Connection"`
+	OutputConnections []Connection `xml:"OutputConnections>Connection"`
+}
+
+type Connection struct {
+	Name          string `xml:"Name,attr"`
+	AllowMultiple string `xml:"AllowMultiple,attr"`
+	Optional      string `xml:"Optional,attr"`
+	Type          string `xml:"Type,attr"`
+	Label         string `xml:"Label,attr"`
+}
+
+type MetaInfo struct {
+	Name         string `xml:"Name"`
+	Description  string `xml:"Description"`
+	CategoryName string `xml:"CategoryName"`
+	SearchTags   string `xml:"SearchTags"`
+	ToolVersion  string `xml:"ToolVersion"`
+	Author       string `xml:"Author"`
+	Company      string `xml:"Company"`
+	Copyright    string `xml:"Copyright"`
+}
+
+func ReadConfigXml(path string) (ConfigXml, error) {
+	config := ConfigXml{}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	err = xml.Unmarshal(content, &config)
+	if err != nil {
+		return config, fmt.Errorf(`error parsing %v: %v`, path, err.Error())
+	}
+	return config, nil
+}
+
+func GenerateConfigXml(tool Tool, htmlFile string) ([]byte, error) {
+	err := validateTool(tool)
+	if err != nil {
+		return nil, err
+	}
+	version := tool.Version
+	if version == `` {
+		version = `1.0`
+	}
+	config := ConfigXml{
+		EngineSettings: EngineSettings{
+			EngineDll:           tool.EngineDll,
+			EngineDllEntryPoint: tool.EntryPoint,
+			SDKVersion:          sdkVersion,
+		},
+		GuiSettings: GuiSettings{
+			Html:              htmlFile,
+			Icon:              tool.Icon,
+			Help:              tool.Help,
+			SDKVersion:        sdkVersion,
+			InputConnections:  connections(tool.Inputs),
+			OutputConnections: connections(tool.Outputs),
+		},
+		MetaInfo: MetaInfo{
+			Name:         tool.Title,
+			Description:  tool.Description,
+			CategoryName: tool.Category,
+			SearchTags:   strings.Join(tool.SearchTags, `, `),
+			ToolVersion:  version,
+			Author:       tool.Author,
+			Company:      tool.Company,
+			Copyright:    tool.Copyright,
+		},
+	}
+	content, err := xml.MarshalIndent(config, ``, `  `)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+func GenerateHtml(tool Tool) ([]byte, error) {
+	widgets, err := configWidgets(tool.Config)
+	if err != nil {
+		return nil, err
+	}
+	builder := &strings.Builder{}
+	builder.WriteString("<!DOCTYPE html>\n<html style=\"padding:20px\">\n<head>\n  <meta charset=\"utf-8\">\n")
+	builder.WriteString(fmt.Sprintf("  <title>%v</title>\n", escapeHtml(tool.Title)))
+	builder.WriteString("  <script type=\"text/javascript\">\n    document.write('<link rel=\"import\" href=\"' + window.Alteryx.LibDir + '2/lib/includes.html\">');\n  </script>\n")
+	writeNestedConfigScript(builder, widgets)
+	builder.WriteString("</head>\n<body>\n  <form>\n    <fieldset>\n")
+	builder.WriteString(fmt.Sprintf("      <legend class='blueTitle'>XMSG(\"%v\")</legend>\n", escapeHtml(tool.Title)))
+	if len(widgets) == 0 {
+		builder.WriteString("      <div class=''>\n        <label>XMSG(\"No configuration required\")</label>\n      </div>\n")
+	}
+	group := ``
+	for _, widget := range widgets {
+		if widget.group != group {
+			builder.WriteString("    </fieldset>\n    <fieldset>\n")
+			if widget.group != `` {
+				builder.WriteString(fmt.Sprintf("      <legend>XMSG(\"%v\")</legend>\n", escapeHtml(widget.group)))
+			}
+			group = widget.group
+		}
+		builder.WriteString("      <div class=''>\n")
+		builder.WriteString(fmt.Sprintf("        <label>XMSG(\"%v\")</label>\n", escapeHtml(widget.label)))
+		builder.WriteString(fmt.Sprintf("        <ayx data-ui-props=\"%v\" data-item-props=\"%v\"></ayx>\n", escapeHtml(widget.uiProps()), escapeHtml(widget.itemProps())))
+		builder.WriteString("      </div>\n")
+	}
+	builder.WriteString("    </fieldset>\n  </form>\n</body>\n</html>\n")
+	return []byte(builder.String()), nil
+}
+
+func validateTool(tool Tool) error {
+	if tool.EngineDll == `` {
+		return fmt.Errorf(`EngineDll is required`)
+	}
+	if tool.EntryPoint == `` {
+		return fmt.Errorf(`EntryPoint is required`)
+	}
+	for _, anchors := range [][]Anchor{tool.Inputs, tool.Outputs} {
+		names := map[string]bool{}
+		for _, anchor := range anchors {
+			if anchor.Name == `` {
+				return fmt.Errorf(`anchor names cannot be empty`)
+			}
+			if names[anchor.Name] {
+				return fmt.Errorf(`anchor '%v' is defined more than once`, anchor.Name)
+			}
+			names[anchor.Name] = true
+		}
+	}
+	return nil
+}
+
+func connections(anchors []Anchor) []Connection {
+	result := make([]Connection, len(anchors))
+	for index, anchor := range anchors {
+		result[index] = Connection{
+			Name:          anchor.Name,
+			AllowMultiple: boolString(anchor.AllowMultiple),
+			Optional:      boolString(anchor.Optional),
+			Type:          `Connection`,
+			Label:         anchor.Label,
+		}
+	}
+	return result
+}
+
+func boolString(value bool) string {
+	if value {
+		return `True`
+	}
+	return `False`
+}
+
+var htmlEscaper = strings.NewReplacer(`&`, `&amp;`, `<`, `&lt;`, `>`, `&gt;`, `"`, `&quot;`)
+
+func escapeHtml(value string) string {
+	return htmlEscaper.Replace(value)
+}
+
+func configType(config interface{}) (reflect.Type, error) {
+	configType := reflect.TypeOf(config)
+	if configType == nil {
+		return nil, nil
+	}
+	if configType.Kind() == reflect.Ptr {
+		configType = configType.Elem()
+	}
+	if configType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(`expected Config to be a struct or a pointer to a struct but got %v`, reflect.TypeOf(config))
+	}
+	return configType, nil
+}
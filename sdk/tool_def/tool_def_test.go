@@ -0,0 +1,206 @@
+package tool_def_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk/tool_def"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type config struct {
+	Server   string   `ayx:"Server,required" label:"Server name"`
+	Password string   `ayx:"Password,password"`
+	Mode     string   `ayx:"Mode,enum=Fast|Safe,default=Safe"`
+	Verbose  bool     `ayx:"Verbose"`
+	Limit    int      `ayx:"Limit"`
+	Field    string   `ayx:"Field" widget:"FieldSelector"`
+	Columns  []string `ayx:"Column" widget:"FieldSelector"`
+	Notes    string   `widget:"TextArea"`
+	Ignored  string   `ayx:"-"`
+}
+
+var tool = tool_def.Tool{
+	EngineDll:   `goalteryx.dll`,
+	EntryPoint:  `PluginEntry`,
+	Title:       `Go Plugin`,
+	Description: `A test tool`,
+	Category:    `Go Examples`,
+	SearchTags:  []string{`go`, `sdk`},
+	Author:      `tlarsendataguy`,
+	Inputs:      []tool_def.Anchor{{Name: `Input`}},
+	Outputs:     []tool_def.Anchor{{Name: `Output`, Label: `O`, AllowMultiple: true, Optional: true}},
+	Config:      &config{},
+}
+
+func TestGenerate(t *testing.T) {
+	dir, err := ioutil.TempDir(``, ``)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	defer os.RemoveAll(dir)
+	dir = filepath.Join(dir, `GoPlugin`)
+	_ = os.Mkdir(dir, 0755)
+
+	err = tool_def.Generate(tool, dir)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	configXml, err := ioutil.ReadFile(filepath.Join(dir, `GoPluginConfig.xml`))
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<AlteryxJavaScriptPlugin>
+  <EngineSettings EngineDll="goalteryx.dll" EngineDllEntryPoint="PluginEntry" SDKVersion="10.1"></EngineSettings>
+  <GuiSettings Html="GoPlugin.html" Icon="" Help="" SDKVersion="10.1">
+    <InputConnections>
+      <Connection Name="Input" AllowMultiple="False" Optional="False" Type="Connection" Label=""></Connection>
+    </InputConnections>
+    <OutputConnections>
+      <Connection Name="Output" AllowMultiple="True" Optional="True" Type="Connection" Label="O"></Connection>
+    </OutputConnections>
+  </GuiSettings>
+  <Properties>
+    <MetaInfo>
+      <Name>Go Plugin</Name>
+      <Description>A test tool</Description>
+      <CategoryName>Go Examples</CategoryName>
+      <SearchTags>go, sdk</SearchTags>
+      <ToolVersion>1.0</ToolVersion>
+      <Author>tlarsendataguy</Author>
+      <Company></Company>
+      <Copyright></Copyright>
+    </MetaInfo>
+  </Properties>
+</AlteryxJavaScriptPlugin>`
+	if string(configXml) != expected {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, string(configXml))
+	}
+
+	html, err := ioutil.ReadFile(filepath.Join(dir, `GoPlugin.html`))
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	for _, line := range []string{
+		`<title>Go Plugin</title>`,
+		`<label>XMSG("Server name")</label>`,
+		`<ayx data-ui-props="{type: 'TextBox'}" data-item-props="{dataName: 'Server', dataType: 'SimpleString'}"></ayx>`,
+		`<ayx data-ui-props="{type: 'TextBox', password: true}" data-item-props="{dataName: 'Password', dataType: 'SimpleString', password: true, encryptionMode: 'user'}"></ayx>`,
+		`<ayx data-ui-props="{type: 'DropDown'}" data-item-props="{dataName: 'Mode', dataType: 'StringSelector', optionList: [{label: 'Fast', value: 'Fast'}, {label: 'Safe', value: 'Safe'}]}"></ayx>`,
+		`<ayx data-ui-props="{type: 'CheckBox'}" data-item-props="{dataName: 'Verbose', dataType: 'SimpleBool'}"></ayx>`,
+		`<ayx data-ui-props="{type: 'NumericSpinner'}" data-item-props="{dataName: 'Limit', dataType: 'SimpleInt'}"></ayx>`,
+		`<ayx data-ui-props="{type: 'DropDown'}" data-item-props="{dataName: 'Field', dataType: 'FieldSelector', anchorIndex: 0, connectionIndex: 0}"></ayx>`,
+		`<ayx data-ui-props="{type: 'ListBox'}" data-item-props="{dataName: 'Column', dataType: 'MultiFieldSelector', anchorIndex: 0, connectionIndex: 0}"></ayx>`,
+		`<ayx data-ui-props="{type: 'TextArea'}" data-item-props="{dataName: 'Notes', dataType: 'SimpleString'}"></ayx>`,
+	} {
+		if !strings.Contains(string(html), line) {
+			t.Fatalf("expected html to contain\n%v\nbut got\n%v", line, string(html))
+		}
+	}
+	if strings.Contains(string(html), `Ignored`) {
+		t.Fatalf(`expected Ignored to be skipped but it was not`)
+	}
+
+	read, err := tool_def.ReadConfigXml(filepath.Join(dir, `GoPluginConfig.xml`))
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if read.EngineSettings.EngineDllEntryPoint != `PluginEntry` || len(read.GuiSettings.OutputConnections) != 1 || read.GuiSettings.OutputConnections[0].Name != `Output` {
+		t.Fatalf(`expected the generated config to be readable but got %v`, read)
+	}
+}
+
+func TestGenerateHtmlWithoutConfig(t *testing.T) {
+	html, err := tool_def.GenerateHtml(tool_def.Tool{Title: `Empty`})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if !strings.Contains(string(html), `No configuration required`) {
+		t.Fatalf(`expected a placeholder label but got %v`, string(html))
+	}
+}
+
+func TestGenerateInvalidDefinitions(t *testing.T) {
+	_, err := tool_def.GenerateConfigXml(tool_def.Tool{EngineDll: `goalteryx.dll`}, `Tool.html`)
+	if err == nil || err.Error() != `EntryPoint is required` {
+		t.Fatalf(`expected an EntryPoint error but got %v`, err)
+	}
+	_, err = tool_def.GenerateConfigXml(tool_def.Tool{EngineDll: `goalteryx.dll`, EntryPoint: `Entry`, Outputs: []tool_def.Anchor{{Name: `Output`}, {Name: `Output`}}}, `Tool.html`)
+	if err == nil || err.Error() != `anchor 'Output' is defined more than once` {
+		t.Fatalf(`expected a duplicate anchor error but got %v`, err)
+	}
+	_, err = tool_def.GenerateHtml(tool_def.Tool{Config: struct {
+		Mode string `widget:"DropDown"`
+	}{}})
+	if err == nil || err.Error() != `struct field 'Mode' uses a DropDown widget but has no enum option` {
+		t.Fatalf(`expected an enum error but got %v`, err)
+	}
+	_, err = tool_def.GenerateHtml(tool_def.Tool{Config: struct {
+		Verbose bool `widget:"TextBox"`
+	}{}})
+	if err == nil || err.Error() != `struct field 'Verbose' of type bool cannot use the 'TextBox' widget` {
+		t.Fatalf(`expected a widget error but got %v`, err)
+	}
+}
+
+func TestGenerateInCurrentDirectory(t *testing.T) {
+	tempDir, err := ioutil.TempDir(``, `tool_def`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+	dir := filepath.Join(tempDir, `GoPlugin`)
+	_ = os.Mkdir(dir, 0755)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	defer os.Chdir(wd)
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	args := os.Args
+	defer func() { os.Args = args }()
+	os.Args = []string{`generator`}
+
+	err = tool_def.Main(tool)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	for _, file := range []string{`GoPluginConfig.xml`, `GoPlugin.html`} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Fatalf(`expected %v to be generated but got: %v`, file, err.Error())
+		}
+	}
+}
+
+type connectionConfig struct {
+	Server string `ayx:"Server,required"`
+	Port   int    `ayx:"Port,default=5432"`
+}
+
+type nestedConfig struct {
+	Name       string           `ayx:"Name"`
+	Connection connectionConfig `ayx:"Connection" label:"Database"`
+}
+
+func TestGenerateHtmlWithNestedConfig(t *testing.T) {
+	html, err := tool_def.GenerateHtml(tool_def.Tool{Title: `Nested`, Config: nestedConfig{}})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	for _, line := range []string{
+		`<ayx data-ui-props="{type: 'TextBox'}" data-item-props="{dataName: 'Name', dataType: 'SimpleString'}"></ayx>`,
+		`<legend>XMSG("Database")</legend>`,
+		`<ayx data-ui-props="{type: 'TextBox'}" data-item-props="{dataName: 'Connection.Server', dataType: 'SimpleString'}"></ayx>`,
+		`<ayx data-ui-props="{type: 'NumericSpinner'}" data-item-props="{dataName: 'Connection.Port', dataType: 'SimpleInt'}"></ayx>`,
+		`var nestedItems = ['Connection.Server', 'Connection.Port'];`,
+	} {
+		if !strings.Contains(string(html), line) {
+			t.Fatalf("expected html to contain\n%v\nbut got\n%v", line, string(html))
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package tool_def
+
+import (
+	"fmt"
+	c "github.com/tlarsendataguy/goalteryx/sdk/config_tag"
+	"reflect"
+	"strings"
+)
+
+const (
+	labelTag  = `label`
+	widgetTag = `widget`
+)
+
+type widget struct {
+	dataName string
+	label    string
+	group    string
+	uiType   string
+	dataType string
+	enum     []string
+	password bool
+}
+
+func configWidgets(config interface{}) ([]widget, error) {
+	structType, err := configType(config)
+	if err != nil || structType == nil {
+		return nil, err
+	}
+	return structWidgets(structType, ``, ``)
+}
+
+func structWidgets(structType reflect.Type, prefix string, group string) ([]widget, error) {
+	fields, err := c.Fields(structType)
+	if err != nil {
+		return nil, err
+	}
+	var widgets []widget
+	for _, configField := range fields {
+		field := structType.FieldByIndex(configField.Index)
+		if field.Type.Kind() == reflect.Struct {
+			label := fieldLabel(field, configField.Tag)
+			if group != `` {
+				label = group + ` / ` + label
+			}
+			nested, err := structWidgets(field.Type, prefix+configField.Tag.Name+`.`, label)
+			if err != nil {
+				return nil, err
+			}
+			widgets = append(widgets, nested...)
+			continue
+		}
+		configWidget, err := fieldWidget(field, configField.Tag)
+		if err != nil {
+			return nil, err
+		}
+		configWidget.dataName = prefix + configWidget.dataName
+		configWidget.group = group
+		widgets = append(widgets, configWidget)
+	}
+	return widgets, nil
+}
+
+func fieldLabel(field reflect.StructField, tag c.Tag) string {
+	if label := field.Tag.Get(labelTag); label != `` {
+		return label
+	}
+	return tag.Name
+}
+
+func fieldWidget(field reflect.StructField, tag c.Tag) (widget, error) {
+	configWidget := widget{
+		dataName: tag.Name,
+		label:    fieldLabel(field, tag),
+		enum:     tag.Enum,
+		password: tag.Password,
+	}
+
+	requested := field.Tag.Get(widgetTag)
+	invalid := fmt.Errorf(`struct field '%v' of type %v cannot use the '%v' widget`, field.Name, field.Type, requested)
+	switch kind := field.Type.Kind(); {
+	case kind == reflect.String:
+		switch {
+		case requested == `FieldSelector`:
+			configWidget.uiType, configWidget.dataType = `DropDown`, `FieldSelector`
+		case requested == `DropDown` || (requested == `` && len(configWidget.enum) > 0):
+			configWidget.uiType, configWidget.dataType = `DropDown`, `StringSelector`
+		case requested == `` || requested == `TextBox` || requested == `TextArea`:
+			configWidget.uiType, configWidget.dataType = `TextBox`, `SimpleString`
+			if requested == `TextArea` {
+				configWidget.uiType = `TextArea`
+			}
+		default:
+			return configWidget, invalid
+		}
+	case kind == reflect.Bool:
+		if requested != `` && requested != `CheckBox` {
+			return configWidget, invalid
+		}
+		configWidget.uiType, configWidget.dataType = `CheckBox`, `SimpleBool`
+	case c.IsIntKind(kind) || c.IsFloatKind(kind):
+		if requested != `` && requested != `NumericSpinner` {
+			return configWidget, invalid
+		}
+		configWidget.uiType, configWidget.dataType = `NumericSpinner`, `SimpleInt`
+		if c.IsFloatKind(kind) {
+			configWidget.dataType = `SimpleFloat`
+		}
+	case kind == reflect.Slice && field.Type.Elem().Kind() == reflect.String:
+		switch requested {
+		case `FieldSelector`:
+			configWidget.uiType, configWidget.dataType = `ListBox`, `MultiFieldSelector`
+		case ``, `ListBox`:
+			configWidget.uiType, configWidget.dataType = `ListBox`, `MultiStringSelector`
+		default:
+			return configWidget, invalid
+		}
+	default:
+		return configWidget, fmt.Errorf(`struct field '%v' has type %v, which cannot be generated as a GUI widget`, field.Name, field.Type)
+	}
+	if configWidget.password && configWidget.dataType != `SimpleString` {
+		return configWidget, fmt.Errorf(`struct field '%v' is tagged as a password but does not use a TextBox`, field.Name)
+	}
+	if (configWidget.dataType == `StringSelector` || configWidget.dataType == `MultiStringSelector`) && len(configWidget.enum) == 0 {
+		return configWidget, fmt.Errorf(`struct field '%v' uses a %v widget but has no enum option`, field.Name, configWidget.uiType)
+	}
+	return configWidget, nil
+}
+
+func (w widget) uiProps() string {
+	if w.password {
+		return fmt.Sprintf(`{type: '%v', password: true}`, w.uiType)
+	}
+	return fmt.Sprintf(`{type: '%v'}`, w.uiType)
+}
+
+func (w widget) itemProps() string {
+	props := fmt.Sprintf(`dataName: '%v', dataType: '%v'`, jsString(w.dataName), w.dataType)
+	switch {
+	case w.password:
+		props += `, password: true, encryptionMode: 'user'`
+	case w.dataType == `FieldSelector` || w.dataType == `MultiFieldSelector`:
+		props += `, anchorIndex: 0, connectionIndex: 0`
+	case len(w.enum) > 0:
+		options := make([]string, len(w.enum))
+		for index, option := range w.enum {
+			options[index] = fmt.Sprintf(`{label: '%v', value: '%v'}`, jsString(option), jsString(option))
+		}
+		props += fmt.Sprintf(`, optionList: [%v]`, strings.Join(options, `, `))
+	}
+	return `{` + props + `}`
+}
+
+func jsString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+}
+
+// Nested config structs are stored as nested XML elements, but the GUI SDK binds
+// data items to top-level keys, so nested items are flattened on load and
+// re-nested before the configuration is saved.
+const nestedConfigScript = `  <script type="text/javascript">
+    var nestedItems = [%v];
+    window.Alteryx.Gui.BeforeLoad = function (manager, AlteryxDataItems, json) {
+      var config = json.Configuration || {};
+      nestedItems.forEach(function (name) {
+        var value = name.split('.').reduce(function (parent, key) {
+          return parent && typeof parent === 'object' ? parent[key] : undefined;
+        }, config);
+        if (value !== undefined) {
+          config[name] = value;
+        }
+      });
+    };
+    window.Alteryx.Gui.BeforeGetConfiguration = function (json) {
+      var config = json.Configuration || {};
+      nestedItems.forEach(function (name) {
+        if (!(name in config)) {
+          return;
+        }
+        var keys = name.split('.');
+        var parent = config;
+        keys.slice(0, -1).forEach(function (key) {
+          if (!parent[key] || typeof parent[key] !== 'object') {
+            parent[key] = {};
+          }
+          parent = parent[key];
+        });
+        parent[keys[keys.length - 1]] = config[name];
+        delete config[name];
+      });
+      return json;
+    };
+  </script>
+`
+
+func writeNestedConfigScript(builder *strings.Builder, widgets []widget) {
+	var names []string
+	for _, widget := range widgets {
+		if widget.group != `` {
+			names = append(names, fmt.Sprintf(`'%v'`, jsString(widget.dataName)))
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	builder.WriteString(fmt.Sprintf(nestedConfigScript, strings.Join(names, `, `)))
+}
@@ -1,7 +1,11 @@
 package sdk
 
 type toolOptions struct {
-	noCache bool
+	noCache            bool
+	noBrowseEverywhere bool
+	presorts           map[string]PresortInfo
+	configMigrator     *ConfigMigrator
+	decryptor          PasswordDecryptor
 }
 
 type ToolOptionSetter func(toolOptions) toolOptions
@@ -12,3 +16,34 @@ func ToolNoCache() ToolOptionSetter {
 		return options
 	}
 }
+
+func ToolNoBrowseEverywhere() ToolOptionSetter {
+	return func(options toolOptions) toolOptions {
+		options.noBrowseEverywhere = true
+		return options
+	}
+}
+
+func ToolPresort(anchor string, info PresortInfo) ToolOptionSetter {
+	return func(options toolOptions) toolOptions {
+		if options.presorts == nil {
+			options.presorts = make(map[string]PresortInfo)
+		}
+		options.presorts[anchor] = info
+		return options
+	}
+}
+
+func ToolMigrateConfig(migrator ConfigMigrator) ToolOptionSetter {
+	return func(options toolOptions) toolOptions {
+		options.configMigrator = &migrator
+		return options
+	}
+}
+
+func ToolDecryptPasswordsWith(decryptor PasswordDecryptor) ToolOptionSetter {
+	return func(options toolOptions) toolOptions {
+		options.decryptor = decryptor
+		return options
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+// +build !windows
+
+package util
+
+import "errors"
+
+func Encrypt(value string) (string, error) {
+	return ``, errors.New(`passwords can only be decrypted by Alteryx on Windows`)
+}
@@ -0,0 +1,249 @@
+package sdk
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+type RecordTransform func(record Record, output *OutgoingRecordInfo) bool
+
+type workerPoolOptions struct {
+	workers   int
+	batchSize int
+	unordered bool
+}
+
+type WorkerPoolOptionSetter func(workerPoolOptions) workerPoolOptions
+
+func WorkerCount(workers int) WorkerPoolOptionSetter {
+	return func(options workerPoolOptions) workerPoolOptions {
+		options.workers = workers
+		return options
+	}
+}
+
+func WorkerBatchSize(records int) WorkerPoolOptionSetter {
+	return func(options workerPoolOptions) workerPoolOptions {
+		options.batchSize = records
+		return options
+	}
+}
+
+func WorkerUnordered() WorkerPoolOptionSetter {
+	return func(options workerPoolOptions) workerPoolOptions {
+		options.unordered = true
+		return options
+	}
+}
+
+type WorkerPool struct {
+	metadata  IncomingRecordInfo
+	output    OutputAnchor
+	transform RecordTransform
+	options   workerPoolOptions
+	source    *OutgoingRecordInfo
+	batch     []Record
+	jobs      chan workerJob
+	results   chan batchResult
+	workers   *sync.WaitGroup
+	submitted int
+	written   int
+	inFlight  int
+	pending   map[int]batchResult
+}
+
+func NewWorkerPool(metadata IncomingRecordInfo, output OutputAnchor, transform RecordTransform, options ...WorkerPoolOptionSetter) *WorkerPool {
+	poolOptions := workerPoolOptions{
+		workers:   runtime.NumCPU(),
+		batchSize: 256,
+	}
+	for _, setter := range options {
+		poolOptions = setter(poolOptions)
+	}
+	if poolOptions.workers < 1 {
+		poolOptions.workers = 1
+	}
+	if poolOptions.batchSize < 1 {
+		poolOptions.batchSize = 1
+	}
+	return &WorkerPool{
+		metadata:  metadata,
+		output:    output,
+		transform: transform,
+		options:   poolOptions,
+	}
+}
+
+type recordSnapshot struct {
+	values  []byte
+	lengths []int
+}
+
+type workerJob struct {
+	index   int
+	records []Record
+}
+
+type batchResult struct {
+	index     int
+	snapshots []*recordSnapshot
+	panicked  interface{}
+}
+
+func (p *WorkerPool) Process(packet RecordPacket) {
+	info := p.output.Metadata()
+	if info == nil {
+		panic(fmt.Sprintf(`the worker pool for output anchor '%v' is processing records before the anchor has been opened; call Open() before processing records`, p.output.Name()))
+	}
+	if info != p.source {
+		p.Close()
+		p.start(info)
+	}
+	for packet.Next() {
+		p.batch = append(p.batch, p.metadata.CopyRecord(packet.Record()))
+		if len(p.batch) == p.options.batchSize {
+			p.submit()
+		}
+	}
+	p.collect()
+}
+
+func (p *WorkerPool) Close() {
+	if p.source == nil {
+		return
+	}
+	if len(p.batch) > 0 {
+		p.submit()
+	}
+	for p.inFlight > 0 {
+		p.receive(<-p.results)
+	}
+	p.stop()
+}
+
+func (p *WorkerPool) start(info *OutgoingRecordInfo) {
+	p.source = info
+	p.jobs = make(chan workerJob, p.options.workers)
+	p.results = make(chan batchResult, p.options.workers*2)
+	p.workers = &sync.WaitGroup{}
+	p.submitted = 0
+	p.written = 0
+	p.pending = make(map[int]batchResult)
+	p.workers.Add(p.options.workers)
+	for worker := 0; worker < p.options.workers; worker++ {
+		go p.work(info.clone())
+	}
+}
+
+func (p *WorkerPool) stop() {
+	close(p.jobs)
+	for p.inFlight > 0 {
+		<-p.results
+		p.inFlight--
+	}
+	p.workers.Wait()
+	p.source = nil
+	p.batch = nil
+	p.pending = nil
+}
+
+func (p *WorkerPool) submit() {
+	for p.inFlight == cap(p.results) {
+		p.receive(<-p.results)
+	}
+	p.jobs <- workerJob{index: p.submitted, records: p.batch}
+	p.submitted++
+	p.inFlight++
+	p.batch = make([]Record, 0, p.options.batchSize)
+}
+
+func (p *WorkerPool) collect() {
+	for {
+		select {
+		case result := <-p.results:
+			p.receive(result)
+		default:
+			return
+		}
+	}
+}
+
+func (p *WorkerPool) receive(result batchResult) {
+	p.inFlight--
+	if result.panicked != nil {
+		p.stop()
+		panic(result.panicked)
+	}
+	if p.options.unordered {
+		p.write(result.snapshots)
+		return
+	}
+	p.pending[result.index] = result
+	for {
+		ready, ok := p.pending[p.written]
+		if !ok {
+			return
+		}
+		p.write(ready.snapshots)
+		delete(p.pending, p.written)
+		p.written++
+	}
+}
+
+func (p *WorkerPool) work(info *OutgoingRecordInfo) {
+	defer p.workers.Done()
+	for job := range p.jobs {
+		p.results <- p.transformBatch(info, job)
+	}
+}
+
+func (p *WorkerPool) transformBatch(info *OutgoingRecordInfo, job workerJob) (result batchResult) {
+	result.index = job.index
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result.panicked = fmt.Sprintf("%v\n%v", recovered, string(debug.Stack()))
+		}
+	}()
+	result.snapshots = make([]*recordSnapshot, 0, len(job.records))
+	for _, record := range job.records {
+		if p.transform(record, info) {
+			result.snapshots = append(result.snapshots, info.snapshot())
+		}
+	}
+	return result
+}
+
+func (p *WorkerPool) write(snapshots []*recordSnapshot) {
+	for _, snapshot := range snapshots {
+		p.source.restore(snapshot)
+		p.output.Write()
+	}
+}
+
+func (i *OutgoingRecordInfo) snapshot() *recordSnapshot {
+	totalSize := 0
+	for _, field := range i.outgoingFields {
+		totalSize += len(field.CurrentValue)
+	}
+	snapshot := &recordSnapshot{
+		values:  make([]byte, 0, totalSize),
+		lengths: make([]int, len(i.outgoingFields)),
+	}
+	for index, field := range i.outgoingFields {
+		snapshot.values = append(snapshot.values, field.CurrentValue...)
+		snapshot.lengths[index] = len(field.CurrentValue)
+	}
+	return snapshot
+}
+
+func (i *OutgoingRecordInfo) restore(snapshot *recordSnapshot) {
+	start := 0
+	for index, field := range i.outgoingFields {
+		end := start + snapshot.lengths[index]
+		field.checkAndResize(end - start)
+		copy(field.CurrentValue, snapshot.values[start:end])
+		start = end
+	}
+}
@@ -0,0 +1,154 @@
+package sdk_test
+
+import (
+	"fmt"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type workerPoolTool struct {
+	options []sdk.WorkerPoolOptionSetter
+	output  sdk.OutputAnchor
+	pool    *sdk.WorkerPool
+}
+
+func (w *workerPoolTool) Init(provider sdk.Provider) {
+	w.output = provider.GetOutputAnchor(`Output`)
+}
+
+func (w *workerPoolTool) OnInputConnectionOpened(connection sdk.InputConnection) {
+	incoming := connection.Metadata()
+	text, _ := incoming.GetStringField(`Field9`)
+	number, _ := incoming.GetIntField(`Field2`)
+
+	editor := incoming.Clone()
+	upperName := editor.AddV_WStringField(`Upper`, `workerPoolTool`, 100)
+	info := editor.GenerateOutgoingRecordInfo()
+	w.output.Open(info)
+
+	w.pool = sdk.NewWorkerPool(incoming, w.output, func(record sdk.Record, output *sdk.OutgoingRecordInfo) bool {
+		if _, isNull := number.GetValue(record); isNull {
+			return false
+		}
+		output.CopyFrom(record)
+		value, isNull := text.GetValue(record)
+		if isNull {
+			output.StringFields[upperName].SetNull()
+		} else {
+			output.StringFields[upperName].SetString(strings.ToUpper(value))
+		}
+		return true
+	}, w.options...)
+}
+
+func (w *workerPoolTool) OnRecordPacket(connection sdk.InputConnection) {
+	w.pool.Process(connection.Read())
+}
+
+func (w *workerPoolTool) OnComplete() {
+	w.pool.Close()
+}
+
+func TestWorkerPoolOrdered(t *testing.T) {
+	implementation := &workerPoolTool{options: []sdk.WorkerPoolOptionSetter{sdk.WorkerCount(4), sdk.WorkerBatchSize(1)}}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	if expectedValues := []interface{}{`ABC`, `DE|"FG`, ``}; !reflect.DeepEqual(expectedValues, collector.Data[`Upper`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Upper`])
+	}
+	if expectedValues := []interface{}{2, 2, 42}; !reflect.DeepEqual(expectedValues, collector.Data[`Field2`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Field2`])
+	}
+	if expectedValues := []interface{}{100, -100, -110}; !reflect.DeepEqual(expectedValues, collector.Data[`Field3`]) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, collector.Data[`Field3`])
+	}
+}
+
+func TestWorkerPoolUnordered(t *testing.T) {
+	implementation := &workerPoolTool{options: []sdk.WorkerPoolOptionSetter{sdk.WorkerCount(3), sdk.WorkerBatchSize(1), sdk.WorkerUnordered()}}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+
+	values := make([]int, 0, len(collector.Data[`Field3`]))
+	for _, value := range collector.Data[`Field3`] {
+		values = append(values, value.(int))
+	}
+	sort.Ints(values)
+	if expectedValues := []int{-110, -100, 100}; !reflect.DeepEqual(expectedValues, values) {
+		t.Fatalf(`expected %v but got %v`, expectedValues, values)
+	}
+}
+
+func TestWorkerPoolNoCache(t *testing.T) {
+	for _, batchSize := range []int{1, 2, 256} {
+		implementation := &workerPoolTool{options: []sdk.WorkerPoolOptionSetter{sdk.WorkerCount(2), sdk.WorkerBatchSize(batchSize)}}
+		runner := sdk.RegisterToolTest(implementation, 1, ``, sdk.NoCache(true))
+		collector := runner.CaptureOutgoingAnchor(`Output`)
+		runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+		runner.SimulateLifecycle()
+
+		if expectedValues := []interface{}{`ABC`, `DE|"FG`, ``}; !reflect.DeepEqual(expectedValues, collector.Data[`Upper`]) {
+			t.Fatalf(`batch size %v: expected %v but got %v`, batchSize, expectedValues, collector.Data[`Upper`])
+		}
+	}
+}
+
+type workerPoolPanicTool struct {
+	workerPoolTool
+}
+
+func (w *workerPoolPanicTool) OnInputConnectionOpened(connection sdk.InputConnection) {
+	incoming := connection.Metadata()
+	info := incoming.Clone().GenerateOutgoingRecordInfo()
+	w.output.Open(info)
+	w.pool = sdk.NewWorkerPool(incoming, w.output, func(_ sdk.Record, _ *sdk.OutgoingRecordInfo) bool {
+		panic(`transform failed`)
+	}, sdk.WorkerCount(2), sdk.WorkerBatchSize(1))
+}
+
+func TestWorkerPoolPanicPropagates(t *testing.T) {
+	defer func() {
+		recovered := recover()
+		message, ok := recovered.(string)
+		if !ok || !strings.HasPrefix(message, "transform failed\n") {
+			t.Fatalf(`expected the transform panic to propagate but got %v`, recovered)
+		}
+		if !strings.Contains(message, `worker_pool_test.go`) {
+			t.Fatalf(`expected the stack of the worker that panicked but got %v`, message)
+		}
+	}()
+	implementation := &workerPoolPanicTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	runner.ConnectInput(`Input`, `sdk_test_passthrough_simulation.txt`)
+	runner.SimulateLifecycle()
+}
+
+func TestWorkerPoolManyPackets(t *testing.T) {
+	rows := make([][]interface{}, 5000)
+	expected := make([]interface{}, len(rows))
+	for index := range rows {
+		rows[index] = []interface{}{index, fmt.Sprintf(`value %v`, index)}
+		expected[index] = fmt.Sprintf(`VALUE %v`, index)
+	}
+	fields := []sdk.NewOutgoingField{
+		sdk.NewInt32Field(`Field2`, `source`),
+		sdk.NewV_WStringField(`Field9`, `source`, 100),
+	}
+	implementation := &workerPoolTool{options: []sdk.WorkerPoolOptionSetter{sdk.WorkerCount(4), sdk.WorkerBatchSize(7)}}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInputData(`Input`, fields, rows)
+	runner.SimulateLifecycle()
+
+	if !reflect.DeepEqual(expected, collector.Data[`Upper`]) {
+		t.Fatalf(`expected %v records in their original order but got %v records`, len(expected), len(collector.Data[`Upper`]))
+	}
+}
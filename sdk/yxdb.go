@@ -0,0 +1,334 @@
+package sdk
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unsafe"
+)
+
+const yxdbDescription = `Alteryx Database File`
+const yxdbFileId = 0x00440205
+const yxdbHeaderSize = 512
+const yxdbBlockSize = 0x40000
+const yxdbRecordsPerBlock = 0x10000
+const yxdbUncompressedBit = 0x80000000
+
+type YxdbReader struct {
+	file       *os.File
+	stream     *bufio.Reader
+	metadata   IncomingRecordInfo
+	metaInfo   string
+	numRecords int64
+	read       int64
+	block      []byte
+	blockLen   int
+	blockPos   int
+	compressed []byte
+	record     []byte
+	err        error
+}
+
+func OpenYxdb(path string) (*YxdbReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	reader := &YxdbReader{
+		file:   file,
+		stream: bufio.NewReader(file),
+		block:  make([]byte, yxdbBlockSize),
+	}
+	err = reader.readHeader()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf(`error opening '%v': %v`, path, err.Error())
+	}
+	return reader, nil
+}
+
+func (r *YxdbReader) readHeader() error {
+	header := make([]byte, yxdbHeaderSize)
+	_, err := io.ReadFull(r.stream, header)
+	if err != nil || string(header[:len(yxdbDescription)]) != yxdbDescription {
+		return fmt.Errorf(`the file is not a valid yxdb file`)
+	}
+	metaInfoLen := int64(binary.LittleEndian.Uint32(header[80:84]))
+	r.numRecords = int64(binary.LittleEndian.Uint64(header[104:112]))
+	stat, err := r.file.Stat()
+	if err != nil {
+		return err
+	}
+	if yxdbHeaderSize+metaInfoLen*2 > stat.Size() {
+		return fmt.Errorf(`the meta info length of %v characters is larger than the file`, metaInfoLen)
+	}
+
+	metaInfoBytes := make([]byte, metaInfoLen*2)
+	_, err = io.ReadFull(r.stream, metaInfoBytes)
+	if err != nil {
+		return fmt.Errorf(`the meta info is truncated`)
+	}
+	metaInfo := make([]uint16, metaInfoLen)
+	for index := range metaInfo {
+		metaInfo[index] = binary.LittleEndian.Uint16(metaInfoBytes[index*2:])
+	}
+	r.metaInfo = strings.TrimSpace(strings.TrimRight(string(utf16.Decode(metaInfo)), "\x00"))
+	if len(r.metaInfo) < 11 {
+		return fmt.Errorf(`the meta info is not valid`)
+	}
+	r.metadata, err = incomingRecordInfoFromString(r.metaInfo)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *YxdbReader) Metadata() IncomingRecordInfo {
+	return r.metadata
+}
+
+func (r *YxdbReader) NumRecords() int64 {
+	return r.numRecords
+}
+
+func (r *YxdbReader) Next() bool {
+	if r.err != nil || r.read >= r.numRecords {
+		return false
+	}
+	recordSize := r.metadata.fixedSize
+	if r.metadata.hasVarFields {
+		recordSize += 4
+	}
+	r.record = r.record[:0]
+	if !r.readRecordBytes(recordSize) {
+		return false
+	}
+	if r.metadata.hasVarFields {
+		varLen := int(binary.LittleEndian.Uint32(r.record[r.metadata.fixedSize:]))
+		if !r.readRecordBytes(varLen) {
+			return false
+		}
+	}
+	r.read++
+	return true
+}
+
+func (r *YxdbReader) Record() Record {
+	return Record(unsafe.Pointer(&r.record[0]))
+}
+
+func (r *YxdbReader) Err() error {
+	return r.err
+}
+
+func (r *YxdbReader) Close() error {
+	return r.file.Close()
+}
+
+func (r *YxdbReader) readRecordBytes(size int) bool {
+	start := len(r.record)
+	if start+size > cap(r.record) {
+		grown := make([]byte, start, start+size)
+		copy(grown, r.record)
+		r.record = grown
+	}
+	r.record = r.record[:start+size]
+	for filled := start; filled < start+size; {
+		if r.blockPos == r.blockLen && !r.readBlock() {
+			return false
+		}
+		copied := copy(r.record[filled:start+size], r.block[r.blockPos:r.blockLen])
+		r.blockPos += copied
+		filled += copied
+	}
+	return true
+}
+
+func (r *YxdbReader) readBlock() bool {
+	lenBytes := make([]byte, 4)
+	_, err := io.ReadFull(r.stream, lenBytes)
+	if err != nil {
+		r.err = fmt.Errorf(`expected %v records but the file ended after %v`, r.numRecords, r.read)
+		return false
+	}
+	blockLen := binary.LittleEndian.Uint32(lenBytes)
+	if blockLen&yxdbUncompressedBit != 0 {
+		blockLen &^= yxdbUncompressedBit
+		if blockLen > yxdbBlockSize {
+			r.err = fmt.Errorf(`block of %v bytes is larger than the maximum of %v`, blockLen, yxdbBlockSize)
+			return false
+		}
+		_, err = io.ReadFull(r.stream, r.block[:blockLen])
+		if err != nil {
+			r.err = fmt.Errorf(`block is truncated: %v`, err.Error())
+			return false
+		}
+		r.blockLen = int(blockLen)
+		r.blockPos = 0
+		return true
+	}
+
+	if blockLen > yxdbBlockSize {
+		r.err = fmt.Errorf(`compressed block of %v bytes is larger than the maximum of %v`, blockLen, yxdbBlockSize)
+		return false
+	}
+	if int(blockLen) > cap(r.compressed) {
+		r.compressed = make([]byte, blockLen)
+	}
+	r.compressed = r.compressed[:blockLen]
+	_, err = io.ReadFull(r.stream, r.compressed)
+	if err != nil {
+		r.err = fmt.Errorf(`block is truncated: %v`, err.Error())
+		return false
+	}
+	r.blockLen, err = lzfDecompress(r.compressed, r.block)
+	if err != nil {
+		r.err = err
+		return false
+	}
+	r.blockPos = 0
+	return true
+}
+
+type YxdbWriter struct {
+	file       *os.File
+	stream     *bufio.Writer
+	info       *OutgoingRecordInfo
+	metaInfo   []uint16
+	fixedSize  uint32
+	numRecords int64
+	position   int64
+	block      []byte
+	record     []byte
+	blockIndex []int64
+	err        error
+}
+
+func CreateYxdb(path string, info *OutgoingRecordInfo) (*YxdbWriter, error) {
+	fields, err := xml.Marshal(info.outgoingFields)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	metaInfo := utf16.Encode([]rune(`<RecordInfo>` + string(fields) + `</RecordInfo>` + "\x00"))
+	writer := &YxdbWriter{
+		file:      file,
+		stream:    bufio.NewWriter(file),
+		info:      info,
+		metaInfo:  metaInfo,
+		fixedSize: uint32(info.FixedSize()),
+		block:     make([]byte, 0, yxdbBlockSize),
+	}
+	writer.write(writer.header(0))
+	metaInfoBytes := make([]byte, len(metaInfo)*2)
+	for index, char := range metaInfo {
+		binary.LittleEndian.PutUint16(metaInfoBytes[index*2:], char)
+	}
+	writer.write(metaInfoBytes)
+	if writer.err != nil {
+		_ = file.Close()
+		return nil, writer.err
+	}
+	return writer, nil
+}
+
+func (w *YxdbWriter) Write() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.numRecords%yxdbRecordsPerBlock == 0 {
+		w.flushBlock()
+		w.blockIndex = append(w.blockIndex, w.position)
+	}
+	recordSize := int(w.info.DataSize())
+	if recordSize > cap(w.record) {
+		w.record = make([]byte, recordSize)
+	}
+	w.record = w.record[:recordSize]
+	writeCache(w.record, w.info, w.fixedSize)
+
+	remaining := w.record
+	for len(remaining) > 0 {
+		available := yxdbBlockSize - len(w.block)
+		if available > len(remaining) {
+			available = len(remaining)
+		}
+		w.block = append(w.block, remaining[:available]...)
+		remaining = remaining[available:]
+		if len(w.block) == yxdbBlockSize {
+			w.flushBlock()
+		}
+	}
+	w.numRecords++
+	return w.err
+}
+
+func (w *YxdbWriter) Close() error {
+	w.flushBlock()
+	indexPosition := w.position
+	index := make([]byte, 4+8*len(w.blockIndex))
+	binary.LittleEndian.PutUint32(index, uint32(len(w.blockIndex)))
+	for i, position := range w.blockIndex {
+		binary.LittleEndian.PutUint64(index[4+i*8:], uint64(position))
+	}
+	w.write(index)
+	if w.err == nil {
+		w.err = w.stream.Flush()
+	}
+	if w.err == nil {
+		_, w.err = w.file.WriteAt(w.header(indexPosition), 0)
+	}
+	closeErr := w.file.Close()
+	if w.err != nil {
+		return w.err
+	}
+	return closeErr
+}
+
+func (w *YxdbWriter) header(indexPosition int64) []byte {
+	header := make([]byte, yxdbHeaderSize)
+	copy(header, yxdbDescription)
+	binary.LittleEndian.PutUint32(header[64:68], yxdbFileId)
+	binary.LittleEndian.PutUint32(header[68:72], uint32(time.Now().Unix()))
+	binary.LittleEndian.PutUint32(header[80:84], uint32(len(w.metaInfo)))
+	binary.LittleEndian.PutUint64(header[96:104], uint64(indexPosition))
+	binary.LittleEndian.PutUint64(header[104:112], uint64(w.numRecords))
+	binary.LittleEndian.PutUint32(header[112:116], 1)
+	return header
+}
+
+func (w *YxdbWriter) flushBlock() {
+	if len(w.block) == 0 {
+		return
+	}
+	compressed := lzfCompress(w.block)
+	lenBytes := make([]byte, 4)
+	if len(compressed) < len(w.block) {
+		binary.LittleEndian.PutUint32(lenBytes, uint32(len(compressed)))
+		w.write(lenBytes)
+		w.write(compressed)
+	} else {
+		binary.LittleEndian.PutUint32(lenBytes, uint32(len(w.block))|yxdbUncompressedBit)
+		w.write(lenBytes)
+		w.write(w.block)
+	}
+	w.block = w.block[:0]
+}
+
+func (w *YxdbWriter) write(data []byte) {
+	if w.err != nil {
+		return
+	}
+	written, err := w.stream.Write(data)
+	w.position += int64(written)
+	w.err = err
+}
@@ -0,0 +1,464 @@
+package sdk_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func yxdbTestInfo() *sdk.OutgoingRecordInfo {
+	info, _ := sdk.NewOutgoingRecordInfo([]sdk.NewOutgoingField{
+		sdk.NewBoolField(`Bool`, `source`),
+		sdk.NewInt32Field(`Int32`, `source`),
+		sdk.NewFixedDecimalField(`Decimal`, `source`, 19, 2),
+		sdk.NewStringField(`String`, `source`, 10),
+		sdk.NewV_WStringField(`V_WString`, `source`, 100000),
+		sdk.NewDateField(`Date`, `source`),
+		sdk.NewBlobField(`Blob`, `source`, 1000000),
+	})
+	return info
+}
+
+func yxdbTempFile(t *testing.T) string {
+	dir, err := ioutil.TempDir(``, `yxdb`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, `test.yxdb`)
+}
+
+func writeYxdbTestFile(t *testing.T, path string, info *sdk.OutgoingRecordInfo) []byte {
+	writer, err := sdk.CreateYxdb(path, info)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	largeBlob := bytes.Repeat([]byte(`0123456789abcdef`), 40000)
+	largeBlob[12345] = 0
+
+	info.BoolFields[`Bool`].SetBool(true)
+	info.IntFields[`Int32`].SetInt(42)
+	_ = info.DecimalFields[`Decimal`].SetDecimal(sdk.NewDecimal(123456, 2))
+	info.StringFields[`String`].SetString(`hello`)
+	info.StringFields[`V_WString`].SetString(`wide ✓`)
+	info.DateTimeFields[`Date`].SetDateTime(time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC))
+	info.BlobFields[`Blob`].SetBlob(largeBlob)
+	_ = writer.Write()
+
+	for _, field := range []sdk.NullableField{info.BoolFields[`Bool`], info.IntFields[`Int32`], info.DecimalFields[`Decimal`], info.StringFields[`String`], info.StringFields[`V_WString`], info.DateTimeFields[`Date`], info.BlobFields[`Blob`]} {
+		field.SetNull()
+	}
+	_ = writer.Write()
+
+	info.BoolFields[`Bool`].SetBool(false)
+	info.IntFields[`Int32`].SetInt(-7)
+	_ = info.DecimalFields[`Decimal`].SetDecimal(sdk.NewDecimal(-5, 2))
+	info.DateTimeFields[`Date`].SetDateTime(time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC))
+	info.StringFields[`String`].SetString(``)
+	info.StringFields[`V_WString`].SetString(`ab`)
+	info.BlobFields[`Blob`].SetBlob([]byte{1, 2, 3, 4, 5})
+	_ = writer.Write()
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	return largeBlob
+}
+
+func TestYxdbRoundTrip(t *testing.T) {
+	path := yxdbTempFile(t)
+	largeBlob := writeYxdbTestFile(t, path, yxdbTestInfo())
+
+	reader, err := sdk.OpenYxdb(path)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	defer reader.Close()
+	if reader.NumRecords() != 3 {
+		t.Fatalf(`expected 3 records but got %v`, reader.NumRecords())
+	}
+	fields := reader.Metadata().Fields()
+	if len(fields) != 7 || fields[2].Type != `FixedDecimal` || fields[2].Size != 19 || fields[2].Scale != 2 || fields[4].Source != `source` {
+		t.Fatalf(`unexpected fields: %v`, fields)
+	}
+
+	boolField, _ := reader.Metadata().GetBoolField(`Bool`)
+	intField, _ := reader.Metadata().GetIntField(`Int32`)
+	decimalField, _ := reader.Metadata().GetDecimalField(`Decimal`)
+	stringField, _ := reader.Metadata().GetStringField(`String`)
+	wideField, _ := reader.Metadata().GetStringField(`V_WString`)
+	dateField, _ := reader.Metadata().GetTimeField(`Date`)
+	blobField, _ := reader.Metadata().GetBlobField(`Blob`)
+	var values [][]interface{}
+	for reader.Next() {
+		record := reader.Record()
+		row := []interface{}{}
+		for _, value := range []func() (interface{}, bool){
+			func() (interface{}, bool) { return boolField.GetValue(record) },
+			func() (interface{}, bool) { return intField.GetValue(record) },
+			func() (interface{}, bool) {
+				value, isNull := decimalField.GetValue(record)
+				return value.String(), isNull
+			},
+			func() (interface{}, bool) { return stringField.GetValue(record) },
+			func() (interface{}, bool) { return wideField.GetValue(record) },
+			func() (interface{}, bool) { return dateField.GetValue(record) },
+			func() (interface{}, bool) {
+				value := blobField.GetValue(record)
+				return append([]byte{}, value...), value == nil
+			},
+		} {
+			value, isNull := value()
+			if isNull {
+				value = nil
+			}
+			row = append(row, value)
+		}
+		values = append(values, row)
+	}
+	if reader.Err() != nil {
+		t.Fatalf(`expected no error but got: %v`, reader.Err().Error())
+	}
+	expected := [][]interface{}{
+		{true, 42, `1234.56`, `hello`, `wide ✓`, time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC), largeBlob},
+		{nil, nil, nil, nil, nil, nil, nil},
+		{false, -7, `-0.05`, ``, `ab`, time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), []byte{1, 2, 3, 4, 5}},
+	}
+	if len(values) != 3 || !reflect.DeepEqual(expected[1:], values[1:]) {
+		t.Fatalf(`expected %v but got %v`, expected[1:], values)
+	}
+	if !reflect.DeepEqual(expected[0], values[0]) {
+		t.Fatalf(`expected the first record to match but got %v`, values[0][:6])
+	}
+}
+
+func TestYxdbManyRecords(t *testing.T) {
+	path := yxdbTempFile(t)
+	info, _ := sdk.NewOutgoingRecordInfo([]sdk.NewOutgoingField{
+		sdk.NewInt64Field(`Id`, `source`),
+		sdk.NewV_StringField(`Text`, `source`, 1000),
+	})
+	writer, err := sdk.CreateYxdb(path, info)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	total := 150000
+	for index := 0; index < total; index++ {
+		info.IntFields[`Id`].SetInt(index)
+		info.StringFields[`Text`].SetString(strings.Repeat(`x`, index%50))
+		_ = writer.Write()
+	}
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	reader, err := sdk.OpenYxdb(path)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	defer reader.Close()
+	idField, _ := reader.Metadata().GetIntField(`Id`)
+	textField, _ := reader.Metadata().GetStringField(`Text`)
+	count := 0
+	for reader.Next() {
+		id, _ := idField.GetValue(reader.Record())
+		text, _ := textField.GetValue(reader.Record())
+		if id != count || len(text) != count%50 {
+			t.Fatalf(`expected record %v but got id %v with text of length %v`, count, id, len(text))
+		}
+		count++
+	}
+	if reader.Err() != nil || count != total {
+		t.Fatalf(`expected %v records but got %v and error %v`, total, count, reader.Err())
+	}
+}
+
+func TestOpenInvalidYxdb(t *testing.T) {
+	path := yxdbTempFile(t)
+	_ = ioutil.WriteFile(path, []byte(`not a yxdb file`), 0644)
+	_, err := sdk.OpenYxdb(path)
+	if err == nil || !strings.Contains(err.Error(), `not a valid yxdb file`) {
+		t.Fatalf(`expected an invalid file error but got %v`, err)
+	}
+
+	writeYxdbTestFile(t, path, yxdbTestInfo())
+	content, _ := ioutil.ReadFile(path)
+	_ = ioutil.WriteFile(path, content[:len(content)/2], 0644)
+	reader, err := sdk.OpenYxdb(path)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	defer reader.Close()
+	for reader.Next() {
+	}
+	if reader.Err() == nil {
+		t.Fatalf(`expected an error reading a truncated file but got none`)
+	}
+}
+
+func TestOpenCorruptYxdb(t *testing.T) {
+	path := yxdbTempFile(t)
+	writeYxdbTestFile(t, path, yxdbTestInfo())
+	content, _ := ioutil.ReadFile(path)
+	metaInfoLen := int(binary.LittleEndian.Uint32(content[80:84]))
+
+	corrupt := append([]byte{}, content...)
+	binary.LittleEndian.PutUint32(corrupt[80:84], 0x7fffffff)
+	_ = ioutil.WriteFile(path, corrupt, 0644)
+	_, err := sdk.OpenYxdb(path)
+	if err == nil || !strings.Contains(err.Error(), `meta info length`) {
+		t.Fatalf(`expected a meta info length error but got %v`, err)
+	}
+
+	corrupt = append([]byte{}, content...)
+	binary.LittleEndian.PutUint32(corrupt[512+metaInfoLen*2:], 0x7fffffff)
+	_ = ioutil.WriteFile(path, corrupt, 0644)
+	reader, err := sdk.OpenYxdb(path)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	defer reader.Close()
+	for reader.Next() {
+	}
+	if reader.Err() == nil || !strings.Contains(reader.Err().Error(), `larger than the maximum`) {
+		t.Fatalf(`expected a block size error but got %v`, reader.Err())
+	}
+}
+
+func TestConnectYxdbInput(t *testing.T) {
+	path := yxdbTempFile(t)
+	writeYxdbTestFile(t, path, yxdbTestInfo())
+
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, path)
+	runner.SimulateLifecycle()
+
+	if expected := []interface{}{42, nil, -7}; !reflect.DeepEqual(expected, collector.Data[`Int32`]) {
+		t.Fatalf(`expected %v but got %v`, expected, collector.Data[`Int32`])
+	}
+	if expected := []interface{}{`wide ✓`, nil, `ab`}; !reflect.DeepEqual(expected, collector.Data[`V_WString`]) {
+		t.Fatalf(`expected %v but got %v`, expected, collector.Data[`V_WString`])
+	}
+	if expected := []interface{}{`1234.56`, nil, `-0.05`}; !reflect.DeepEqual(expected, decimalStrings(collector.Data[`Decimal`])) {
+		t.Fatalf(`expected %v but got %v`, expected, collector.Data[`Decimal`])
+	}
+}
+
+var allTypesFields = []sdk.NewOutgoingField{
+	sdk.NewBoolField(`Bool`, `source`),
+	sdk.NewByteField(`Byte`, `source`),
+	sdk.NewInt16Field(`Int16`, `source`),
+	sdk.NewInt32Field(`Int32`, `source`),
+	sdk.NewInt64Field(`Int64`, `source`),
+	sdk.NewFloatField(`Float`, `source`),
+	sdk.NewDoubleField(`Double`, `source`),
+	sdk.NewFixedDecimalField(`FixedDecimal`, `source`, 19, 2),
+	sdk.NewStringField(`String`, `source`, 20),
+	sdk.NewWStringField(`WString`, `source`, 20),
+	sdk.NewV_StringField(`V_String`, `source`, 400000),
+	sdk.NewV_WStringField(`V_WString`, `source`, 100),
+	sdk.NewDateField(`Date`, `source`),
+	sdk.NewDateTimeField(`DateTime`, `source`),
+	sdk.NewTimeField(`Time`, `source`),
+	sdk.NewBlobField(`Blob`, `source`, 1000),
+	sdk.NewSpatialObjField(`SpatialObj`, `source`, 1000),
+}
+
+var allTypesNames = []string{`Bool`, `Byte`, `Int16`, `Int32`, `Int64`, `Float`, `Double`, `FixedDecimal`, `String`, `WString`, `V_String`, `V_WString`, `Date`, `DateTime`, `Time`, `Blob`, `SpatialObj`}
+
+const allTypesRecords = 70000
+const allTypesLargeRow = 5
+
+func allTypesValue(field string, row int) interface{} {
+	if row%3 == 1 {
+		return nil
+	}
+	switch field {
+	case `Bool`:
+		return row%2 == 0
+	case `Byte`:
+		return row % 256
+	case `Int16`:
+		return row%30000 - 15000
+	case `Int32`:
+		return row * -3
+	case `Int64`:
+		return row * 100000000
+	case `Float`:
+		return float64(row) / 4
+	case `Double`:
+		return float64(row) / 8
+	case `FixedDecimal`:
+		return sdk.NewDecimal(int64(row)*7, 2).String()
+	case `String`:
+		return fmt.Sprintf(`s%v`, row)
+	case `WString`:
+		return fmt.Sprintf(`w✓%v`, row)
+	case `V_String`:
+		if row == allTypesLargeRow {
+			return strings.Repeat(`y`, 300000)
+		}
+		return strings.Repeat(`v`, row%40)
+	case `V_WString`:
+		return fmt.Sprintf(`wide ✓ %v`, row)
+	case `Date`:
+		return time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, row%10000)
+	case `DateTime`:
+		return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(row) * time.Second)
+	case `Time`:
+		return time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(row%86400) * time.Second)
+	case `Blob`:
+		return []byte(fmt.Sprintf("b\x00%v", row))
+	default:
+		return []byte(fmt.Sprintf("p\x00%v", row))
+	}
+}
+
+func writeAllTypesYxdb(t *testing.T, path string) {
+	info, _ := sdk.NewOutgoingRecordInfo(allTypesFields)
+	writer, err := sdk.CreateYxdb(path, info)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	for row := 0; row < allTypesRecords; row++ {
+		for _, name := range allTypesNames {
+			value := allTypesValue(name, row)
+			var field sdk.NullableField
+			switch name {
+			case `Bool`:
+				field = info.BoolFields[name]
+				if value != nil {
+					info.BoolFields[name].SetBool(value.(bool))
+				}
+			case `Byte`, `Int16`, `Int32`, `Int64`:
+				field = info.IntFields[name]
+				if value != nil {
+					info.IntFields[name].SetInt(value.(int))
+				}
+			case `Float`, `Double`:
+				field = info.FloatFields[name]
+				if value != nil {
+					info.FloatFields[name].SetFloat(value.(float64))
+				}
+			case `FixedDecimal`:
+				field = info.DecimalFields[name]
+				if value != nil {
+					decimal, _ := sdk.ParseDecimal(value.(string))
+					_ = info.DecimalFields[name].SetDecimal(decimal)
+				}
+			case `String`, `WString`, `V_String`, `V_WString`:
+				field = info.StringFields[name]
+				if value != nil {
+					info.StringFields[name].SetString(value.(string))
+				}
+			case `Date`, `DateTime`, `Time`:
+				field = info.DateTimeFields[name]
+				if value != nil {
+					info.DateTimeFields[name].SetDateTime(value.(time.Time))
+				}
+			default:
+				field = info.BlobFields[name]
+				if value != nil {
+					info.BlobFields[name].SetBlob(value.([]byte))
+				}
+			}
+			if value == nil {
+				field.SetNull()
+			}
+		}
+		err = writer.Write()
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+	}
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+}
+
+func TestYxdbAllFieldTypes(t *testing.T) {
+	path := yxdbTempFile(t)
+	writeAllTypesYxdb(t, path)
+
+	reader, err := sdk.OpenYxdb(path)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	defer reader.Close()
+	if reader.NumRecords() != allTypesRecords {
+		t.Fatalf(`expected %v records but got %v`, allTypesRecords, reader.NumRecords())
+	}
+	metadata := reader.Metadata()
+	fields := metadata.Fields()
+	if len(fields) != len(allTypesFields) {
+		t.Fatalf(`expected %v fields but got %v`, len(allTypesFields), fields)
+	}
+	for _, field := range fields {
+		if field.Name != field.Type {
+			t.Fatalf(`expected field %v to have type %v but got %v`, field.Name, field.Name, field.Type)
+		}
+	}
+	decimalField, _ := metadata.GetDecimalField(`FixedDecimal`)
+	largeField, _ := metadata.GetStringField(`V_String`)
+	spatialField, _ := metadata.GetBlobField(`SpatialObj`)
+	row := 0
+	for reader.Next() {
+		record := reader.Record()
+		decimal, isNull := decimalField.GetValue(record)
+		if expected := allTypesValue(`FixedDecimal`, row); (expected == nil) != isNull || (!isNull && decimal.String() != expected) {
+			t.Fatalf(`expected FixedDecimal %v in row %v but got %v`, expected, row, decimal)
+		}
+		large, _ := largeField.GetValue(record)
+		if expected := allTypesValue(`V_String`, row); expected != nil && large != expected {
+			t.Fatalf(`expected V_String of length %v in row %v but got length %v`, len(expected.(string)), row, len(large))
+		}
+		if expected := allTypesValue(`SpatialObj`, row); !reflect.DeepEqual(expected, nilIfEmpty(spatialField.GetValue(record))) {
+			t.Fatalf(`expected SpatialObj %v in row %v but got %v`, expected, row, spatialField.GetValue(record))
+		}
+		row++
+	}
+	if reader.Err() != nil || row != allTypesRecords {
+		t.Fatalf(`expected %v records but got %v and error %v`, allTypesRecords, row, reader.Err())
+	}
+
+	implementation := &PassThroughTool{}
+	runner := sdk.RegisterToolTest(implementation, 1, ``)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.ConnectInput(`Input`, path)
+	runner.SimulateLifecycle()
+	for _, field := range fields {
+		values := collector.Data[field.Name]
+		if len(values) != allTypesRecords {
+			t.Fatalf(`expected %v values for %v but got %v`, allTypesRecords, field.Name, len(values))
+		}
+		for row, value := range values {
+			expected := allTypesValue(field.Name, row)
+			if field.Type == `FixedDecimal` && value != nil {
+				value = value.(sdk.Decimal).String()
+			}
+			if !reflect.DeepEqual(expected, value) {
+				t.Fatalf(`expected %v %v in row %v but got %v`, field.Name, expected, row, value)
+			}
+		}
+	}
+}
+
+func nilIfEmpty(value []byte) interface{} {
+	if value == nil {
+		return nil
+	}
+	return append([]byte{}, value...)
+}